@@ -0,0 +1,223 @@
+// Package auth verifies Google-issued identity tokens on incoming requests and
+// puts the caller's identity in the request context.
+//
+// IAP verifies the assertion Identity-Aware Proxy adds to every request it
+// forwards:
+//
+//	http.Handle("/admin/", auth.IAP("/projects/123/global/backendServices/456")(adminHandler))
+//
+// GoogleOIDC verifies the bearer token Google services send when calling as a
+// service account:
+//
+//	oidc := auth.GoogleOIDC([]string{"https://worker-abc.a.run.app"}, []string{"scheduler@my-project.iam.gserviceaccount.com"})
+//	http.Handle("/tasks/", oidc(taskHandler))
+//
+// Authorize enforces a route policy on the authenticated caller:
+//
+//	authz := auth.Authorize(auth.Policy{
+//		Rules: []auth.Rule{{Pattern: "/admin/", Roles: []string{"admin"}}},
+//		Roles: map[string][]string{"admin": {"alice@example.com"}},
+//	})
+//	http.Handle("/admin/", iap(authz(adminHandler)))
+//
+// IAP and OIDC identities carry no groups; group rules need Policy.TrustHeaders
+// behind a proxy that sets X-User-Groups.
+//
+// Handlers read the verified caller with IdentityFromContext. The identity is
+// also added to spans and logs as enduser.id and used as the audit principal.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Reason is a machine-readable code for a rejected request
+type Reason string
+
+// Rejection reasons, recorded on the span as auth.failure_reason
+const (
+	ReasonMissingToken         Reason = "missing_token"
+	ReasonMalformedToken       Reason = "malformed_token"
+	ReasonUnsupportedAlgorithm Reason = "unsupported_algorithm"
+	ReasonUnknownKey           Reason = "unknown_key"
+	ReasonKeysUnavailable      Reason = "keys_unavailable"
+	ReasonInvalidSignature     Reason = "invalid_signature"
+	ReasonExpired              Reason = "expired"
+	ReasonIssuedInFuture       Reason = "issued_in_future"
+	ReasonInvalidIssuer        Reason = "invalid_issuer"
+	ReasonInvalidAudience      Reason = "invalid_audience"
+	ReasonEmailNotVerified     Reason = "email_not_verified"
+	ReasonEmailNotAllowed      Reason = "email_not_allowed"
+)
+
+// Error is returned when a token fails verification
+type Error struct {
+	Reason Reason
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return "auth: " + string(e.Reason)
+	}
+	return "auth: " + string(e.Reason) + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// reject returns an *Error for reason
+func reject(reason Reason, err error) *Error {
+	return &Error{Reason: reason, Err: err}
+}
+
+// ReasonOf returns the rejection reason of err, or "" if it is not an *Error
+func ReasonOf(err error) Reason {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+	return ""
+}
+
+// Identity is the verified caller of a request
+type Identity struct {
+	Subject string   // Stable account ID ("sub" claim)
+	Email   string   // User or service account email
+	Groups  []string // Group memberships, when the source provides them
+	Issuer  string   // Token issuer
+	Method  string   // How the caller was authenticated, "iap" or "oidc"
+}
+
+// Principal returns the email, or the subject when there is none
+func (id Identity) Principal() string {
+	if id.Email != "" {
+		return id.Email
+	}
+	return id.Subject
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a context carrying id
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFromContext returns the identity stored by WithIdentity
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// authenticated stores id in ctx and records the caller on the span, in
+// request logs and as the audit principal
+func authenticated(ctx context.Context, id Identity) context.Context {
+	principal := id.Principal()
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("enduser.id", principal),
+		attribute.String("auth.method", id.Method),
+	)
+	ctx = WithIdentity(ctx, id)
+	ctx = logger.WithPrincipal(ctx, principal)
+	return logger.WithAttrs(ctx, "enduser.id", principal)
+}
+
+// options configure the verifying middleware
+type options struct {
+	keys   KeySource
+	now    func() time.Time
+	logger *logger.Logger
+}
+
+// Option configures IAP and the other verifying middleware
+type Option func(*options)
+
+// WithKeySource verifies signatures with keys instead of Google's published JWKS
+func WithKeySource(keys KeySource) Option {
+	return func(o *options) {
+		o.keys = keys
+	}
+}
+
+// WithClock replaces time.Now when checking expiry
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithLogger logs rejected requests to l instead of the global logger
+func WithLogger(l *logger.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// newOptions applies opts over the defaults; keys is used when none is given
+func newOptions(keys func() KeySource, opts []Option) options {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.keys == nil {
+		o.keys = keys()
+	}
+	return o
+}
+
+// log returns the configured logger or the global one
+func (o options) log() *logger.Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return logger.Global()
+}
+
+// rejectRequest records why a request was rejected and writes the response
+func (o options) rejectRequest(w http.ResponseWriter, r *http.Request, method string, err error) {
+	ctx := r.Context()
+	reason := ReasonOf(err)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("auth.method", method),
+		attribute.String("auth.failure_reason", string(reason)),
+	)
+	span.AddEvent("auth.rejected", trace.WithAttributes(attribute.String("auth.failure_reason", string(reason))))
+
+	o.log().WarnContext(ctx, "Request authentication failed",
+		"auth.method", method,
+		"auth.failure_reason", string(reason),
+		"error", err,
+	)
+
+	// Key fetch failures are ours, not the caller's
+	status := http.StatusUnauthorized
+	if reason == ReasonKeysUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	writeProblem(w, status, http.StatusText(status), "request authentication failed: "+string(reason))
+}
+
+// problem is an RFC 9457 problem details body
+type problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// writeProblem writes an application/problem+json response
+func writeProblem(w http.ResponseWriter, status int, title, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem{Type: "about:blank", Title: title, Status: status, Detail: detail})
+}
@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// testNow is the fixed clock used by the verification tests
+var testNow = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// signES256 signs claims as a compact JWS with key
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	signing := encodeSegments(t, map[string]any{"alg": "ES256", "kid": kid, "typ": "JWT"}, claims)
+	digest := sha256.Sum256([]byte(signing))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.Sign() error = %v", err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// signRS256 signs claims as a compact JWS with key
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	signing := encodeSegments(t, map[string]any{"alg": "RS256", "kid": kid, "typ": "JWT"}, claims)
+	digest := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("rsa.SignPKCS1v15() error = %v", err)
+	}
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func encodeSegments(t *testing.T, header, claims map[string]any) string {
+	t.Helper()
+	h, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+	return key
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	return key
+}
+
+// newTestLogger returns a logger writing JSON to buf
+func newTestLogger(t *testing.T, buf *bytes.Buffer) *logger.Logger {
+	t.Helper()
+	l, err := logger.NewLogger(context.Background(), logger.Config{
+		ServiceName: "auth-test",
+		Handlers:    []slog.Handler{slog.NewJSONHandler(buf, nil)},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	return l
+}
+
+// newRecorder returns a tracer provider recording ended spans
+func newRecorder(t *testing.T) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return tp, rec
+}
+
+// serveTraced runs handler inside a span and returns the response and span attributes
+func serveTraced(t *testing.T, handler http.Handler, req *http.Request) (*httptest.ResponseRecorder, map[string]string) {
+	t.Helper()
+	tp, rec := newRecorder(t)
+	ctx, span := tp.Tracer("test").Start(req.Context(), "request")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+	span.End()
+
+	attrs := map[string]string{}
+	for _, s := range rec.Ended() {
+		for _, kv := range s.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+	}
+	return w, attrs
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("boom")
+	err := error(reject(ReasonKeysUnavailable, cause))
+
+	if got := err.Error(); got != "auth: keys_unavailable: boom" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is() = false for the wrapped cause")
+	}
+	if got := ReasonOf(err); got != ReasonKeysUnavailable {
+		t.Errorf("ReasonOf() = %q, want %q", got, ReasonKeysUnavailable)
+	}
+	if got := ReasonOf(cause); got != "" {
+		t.Errorf("ReasonOf(plain error) = %q, want empty", got)
+	}
+	if got := reject(ReasonExpired, nil).Error(); got != "auth: expired" {
+		t.Errorf("Error() without cause = %q", got)
+	}
+}
+
+func TestIdentityContext(t *testing.T) {
+	if _, ok := IdentityFromContext(context.Background()); ok {
+		t.Error("IdentityFromContext() ok = true on an empty context")
+	}
+
+	id := Identity{Subject: "accounts.google.com:123", Email: "alice@example.com", Method: "iap"}
+	got, ok := IdentityFromContext(WithIdentity(context.Background(), id))
+	if !ok || got.Email != id.Email {
+		t.Errorf("IdentityFromContext() = %+v, %v", got, ok)
+	}
+
+	if p := id.Principal(); p != "alice@example.com" {
+		t.Errorf("Principal() = %q, want email", p)
+	}
+	if p := (Identity{Subject: "123"}).Principal(); p != "123" {
+		t.Errorf("Principal() without email = %q, want subject", p)
+	}
+}
+
+func TestAuthenticated(t *testing.T) {
+	id := Identity{Subject: "123", Email: "alice@example.com", Method: "iap"}
+	var ctx context.Context
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = authenticated(r.Context(), id)
+	})
+	_, attrs := serveTraced(t, handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if attrs["enduser.id"] != "alice@example.com" || attrs["auth.method"] != "iap" {
+		t.Errorf("span attributes = %v", attrs)
+	}
+	if p, _ := logger.PrincipalFromContext(ctx); p != "alice@example.com" {
+		t.Errorf("audit principal = %q", p)
+	}
+	found := false
+	for _, a := range logger.AttrsFromContext(ctx) {
+		if a.Key == "enduser.id" && a.Value.String() == "alice@example.com" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("enduser.id missing from log attrs")
+	}
+}
+
+func TestRejectRequest(t *testing.T) {
+	var buf bytes.Buffer
+	o := newOptions(func() KeySource { return StaticKeys{} }, []Option{WithLogger(newTestLogger(t, &buf))})
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"invalid token", reject(ReasonInvalidSignature, nil), http.StatusUnauthorized},
+		{"keys unavailable", reject(ReasonKeysUnavailable, errors.New("timeout")), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				o.rejectRequest(w, r, "iap", tt.err)
+			})
+			w, attrs := serveTraced(t, handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			var p problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if p.Status != tt.status || p.Type != "about:blank" || !strings.Contains(p.Detail, string(ReasonOf(tt.err))) {
+				t.Errorf("problem = %+v", p)
+			}
+			if attrs["auth.failure_reason"] != string(ReasonOf(tt.err)) {
+				t.Errorf("auth.failure_reason = %q", attrs["auth.failure_reason"])
+			}
+			if !strings.Contains(buf.String(), `"level":"WARN"`) || !strings.Contains(buf.String(), string(ReasonOf(tt.err))) {
+				t.Errorf("log = %s, want a warning with the reason", buf.String())
+			}
+		})
+	}
+}
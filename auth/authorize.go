@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers read by Authorize when Policy.TrustHeaders is set
+const (
+	UserEmailHeader  = "X-User-Email"
+	UserGroupsHeader = "X-User-Groups"
+)
+
+// Decision is the result of evaluating a policy
+type Decision string
+
+// Authorization decisions, recorded on the span as authz.decision
+const (
+	DecisionAllow Decision = "allow"
+	DecisionDeny  Decision = "deny"
+)
+
+// Rule grants access to the requests matching Pattern and Methods
+type Rule struct {
+	// Name identifies the rule in spans and audit logs (default: Pattern)
+	Name string
+
+	// Pattern is an http.ServeMux path pattern, e.g. "/orders/{id}" or "/admin/".
+	// When several rules match, the most specific pattern wins, as in ServeMux.
+	Pattern string
+
+	// Methods limits the rule to these methods; empty matches all. GET also matches HEAD.
+	Methods []string
+
+	// Public allows unauthenticated requests
+	Public bool
+
+	// Roles and Groups list what grants access: the caller needs one of the
+	// roles or to be a member of one of the groups. When both are empty any
+	// authenticated caller is allowed.
+	Roles  []string
+	Groups []string
+}
+
+// name returns the rule name used in spans and audit logs
+func (r Rule) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Pattern
+}
+
+// Policy maps routes to the roles or groups allowed to call them. Requests
+// matching no rule are denied.
+type Policy struct {
+	Rules []Rule
+
+	// Roles maps a role to the emails and groups holding it
+	Roles map[string][]string
+
+	// TrustHeaders reads the caller from the X-User-Email and comma-separated
+	// X-User-Groups headers when no middleware has authenticated the request,
+	// and adds X-User-Groups to an IAP or OIDC identity with the same email.
+	// IAP and OIDC tokens carry no groups, so rules with Groups, or Roles
+	// granted to groups, need this or another middleware that fills
+	// Identity.Groups. Only enable it behind a proxy that sets these headers
+	// and strips them from client requests.
+	TrustHeaders bool
+}
+
+// grants reports whether id holds one of roles or is in one of groups.
+// Emails and group addresses are compared case-insensitively.
+func (p Policy) grants(id Identity, roles, groups []string) bool {
+	if len(roles) == 0 && len(groups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		if containsFold(id.Groups, g) {
+			return true
+		}
+	}
+	for _, role := range roles {
+		for _, member := range p.Roles[role] {
+			if (id.Email != "" && strings.EqualFold(member, id.Email)) || containsFold(id.Groups, member) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list holds s, ignoring case
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// Authorize returns middleware that enforces policy on every request. Place
+// it after IAP or GoogleOIDC, whose identity it reads from the context.
+// Unauthenticated requests to non-public rules get 401 and denied requests
+// 403, both as problem details. Every decision is recorded on the span as
+// authz.decision and authz.rule and written as an audit entry.
+//
+// Authorize panics if two rules have the same pattern and method, as
+// http.ServeMux does.
+func Authorize(policy Policy, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(func() KeySource { return nil }, opts)
+	mux := compileRules(policy.Rules)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			rule, matched := matchRule(mux, policy.Rules, r)
+
+			id, authenticatedCaller := IdentityFromContext(ctx)
+			if policy.TrustHeaders {
+				if header, ok := headerIdentity(r); ok {
+					switch {
+					case !authenticatedCaller:
+						id, authenticatedCaller = header, true
+						ctx = authenticated(ctx, id)
+					case strings.EqualFold(header.Email, id.Email):
+						// IAP and OIDC tokens carry no groups; take them from the proxy
+						id.Groups = mergeGroups(id.Groups, header.Groups)
+						ctx = WithIdentity(ctx, id)
+					}
+				}
+			}
+
+			decision, status, detail := DecisionDeny, http.StatusForbidden, "no rule matches this request"
+			switch {
+			case !matched:
+			case rule.Public:
+				decision = DecisionAllow
+			case !authenticatedCaller:
+				status, detail = http.StatusUnauthorized, "authentication required"
+			case policy.grants(id, rule.Roles, rule.Groups):
+				decision = DecisionAllow
+			default:
+				detail = "caller lacks a required role or group"
+			}
+
+			o.recordDecision(ctx, r, rule.name(), decision)
+			if decision == DecisionDeny {
+				writeProblem(w, status, http.StatusText(status), detail)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// compileRules registers each rule's patterns on a ServeMux, which is used
+// only to find the most specific rule for a request
+func compileRules(rules []Rule) *http.ServeMux {
+	mux := http.NewServeMux()
+	for i, rule := range rules {
+		handler := ruleIndex(i)
+		if len(rule.Methods) == 0 {
+			registerRule(mux, rule.Pattern, handler)
+			continue
+		}
+		for _, method := range rule.Methods {
+			registerRule(mux, strings.ToUpper(method)+" "+rule.Pattern, handler)
+		}
+	}
+	return mux
+}
+
+// registerRule adds pattern to mux, naming it in the panic of an invalid policy
+func registerRule(mux *http.ServeMux, pattern string, handler http.Handler) {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(fmt.Sprintf("auth: invalid policy rule %q: %v", pattern, r))
+		}
+	}()
+	mux.Handle(pattern, handler)
+}
+
+// ruleIndex is the placeholder handler identifying a rule in the ServeMux
+type ruleIndex int
+
+func (ruleIndex) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+// matchRule returns the most specific rule matching r
+func matchRule(mux *http.ServeMux, rules []Rule, r *http.Request) (Rule, bool) {
+	h, _ := mux.Handler(r)
+	i, ok := h.(ruleIndex)
+	if !ok {
+		return Rule{}, false
+	}
+	return rules[i], true
+}
+
+// headerIdentity reads the caller from the trusted proxy headers
+func headerIdentity(r *http.Request) (Identity, bool) {
+	email := strings.TrimSpace(r.Header.Get(UserEmailHeader))
+	if email == "" {
+		return Identity{}, false
+	}
+	var groups []string
+	for _, g := range strings.Split(r.Header.Get(UserGroupsHeader), ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return Identity{Email: email, Groups: groups, Method: "header"}, true
+}
+
+// mergeGroups returns groups plus the extra groups it lacks
+func mergeGroups(groups, extra []string) []string {
+	merged := slices.Clone(groups)
+	for _, g := range extra {
+		if !containsFold(merged, g) {
+			merged = append(merged, g)
+		}
+	}
+	return merged
+}
+
+// recordDecision adds the decision to the span and the audit log
+func (o options) recordDecision(ctx context.Context, r *http.Request, rule string, decision Decision) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("authz.decision", string(decision)),
+		attribute.String("authz.rule", rule),
+	)
+
+	outcome := logger.OutcomeSuccess
+	if decision == DecisionDeny {
+		outcome = logger.OutcomeDenied
+	}
+	err := o.log().Audit(ctx, logger.AuditEvent{
+		Action:   "http.authorize",
+		Resource: r.Method + " " + r.URL.Path,
+		Outcome:  outcome,
+		Metadata: map[string]any{"rule": rule, "decision": string(decision)},
+	})
+	if err != nil {
+		o.log().ErrorContext(ctx, "Failed to write authorization audit entry", "error", err)
+	}
+}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthorize(t *testing.T) {
+	var buf bytes.Buffer
+	policy := Policy{
+		Rules: []Rule{
+			{Name: "health", Pattern: "/healthz", Public: true},
+			{Name: "read-orders", Pattern: "/orders/", Methods: []string{"GET"}},
+			{Name: "write-orders", Pattern: "/orders/{id}", Methods: []string{"PUT", "DELETE"}, Roles: []string{"editor"}},
+			{Name: "admin", Pattern: "/admin/", Groups: []string{"admins@example.com"}},
+		},
+		Roles: map[string][]string{
+			"editor": {"bob@example.com", "editors@example.com"},
+		},
+		TrustHeaders: true,
+	}
+
+	var reached *http.Request
+	handler := Authorize(policy, WithLogger(newTestLogger(t, &buf)))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reached = r
+		}),
+	)
+
+	alice := Identity{Email: "alice@example.com", Groups: []string{"admins@example.com"}, Method: "iap"}
+	bob := Identity{Email: "bob@example.com", Method: "iap"}
+	carol := Identity{Email: "carol@example.com", Groups: []string{"editors@example.com"}, Method: "iap"}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		id       *Identity
+		headers  map[string]string
+		status   int
+		rule     string
+		decision Decision
+	}{
+		{"public without identity", "GET", "/healthz", nil, nil, http.StatusOK, "health", DecisionAllow},
+		{"any caller can read", "GET", "/orders/42", &alice, nil, http.StatusOK, "read-orders", DecisionAllow},
+		{"HEAD matches GET", "HEAD", "/orders/42", &alice, nil, http.StatusOK, "read-orders", DecisionAllow},
+		{"unauthenticated", "GET", "/orders/42", nil, nil, http.StatusUnauthorized, "read-orders", DecisionDeny},
+		{"role by email", "DELETE", "/orders/42", &bob, nil, http.StatusOK, "write-orders", DecisionAllow},
+		{"role by group", "PUT", "/orders/42", &carol, nil, http.StatusOK, "write-orders", DecisionAllow},
+		{"role by email ignores case", "DELETE", "/orders/42", &Identity{Email: "Bob@Example.com", Method: "iap"}, nil, http.StatusOK, "write-orders", DecisionAllow},
+		{"group ignores case", "GET", "/admin/users", &Identity{Email: "erin@example.com", Groups: []string{"Admins@Example.com"}, Method: "iap"}, nil, http.StatusOK, "admin", DecisionAllow},
+		{"missing role", "DELETE", "/orders/42", &alice, nil, http.StatusForbidden, "write-orders", DecisionDeny},
+		{"method without rule", "POST", "/orders/42", &bob, nil, http.StatusForbidden, "", DecisionDeny},
+		{"group", "GET", "/admin/users", &alice, nil, http.StatusOK, "admin", DecisionAllow},
+		{"not in group", "GET", "/admin/users", &bob, nil, http.StatusForbidden, "admin", DecisionDeny},
+		{"no matching rule", "GET", "/other", &alice, nil, http.StatusForbidden, "", DecisionDeny},
+		{"trusted headers", "GET", "/admin/users", nil, map[string]string{
+			UserEmailHeader:  "dave@example.com",
+			UserGroupsHeader: "staff@example.com, admins@example.com",
+		}, http.StatusOK, "admin", DecisionAllow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			reached = nil
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.id != nil {
+				req = req.WithContext(WithIdentity(req.Context(), *tt.id))
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w, attrs := serveTraced(t, handler, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if (reached != nil) != (tt.decision == DecisionAllow) {
+				t.Errorf("next called = %v, want %v", reached != nil, tt.decision == DecisionAllow)
+			}
+			if attrs["authz.decision"] != string(tt.decision) || attrs["authz.rule"] != tt.rule {
+				t.Errorf("span attributes = %v", attrs)
+			}
+
+			var entry struct {
+				Audit struct {
+					Action   string         `json:"action"`
+					Resource string         `json:"resource"`
+					Outcome  string         `json:"outcome"`
+					Metadata map[string]any `json:"metadata"`
+				} `json:"audit"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("decode audit entry %q: %v", buf.String(), err)
+			}
+			wantOutcome := "success"
+			if tt.decision == DecisionDeny {
+				wantOutcome = "denied"
+			}
+			if entry.Audit.Outcome != wantOutcome || entry.Audit.Resource != tt.method+" "+tt.path || entry.Audit.Metadata["rule"] != tt.rule {
+				t.Errorf("audit = %+v", entry.Audit)
+			}
+
+			if tt.decision == DecisionDeny {
+				if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+					t.Errorf("Content-Type = %q", ct)
+				}
+			}
+		})
+	}
+
+	t.Run("header identity reaches the handler", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/admin/users", nil)
+		req.Header.Set(UserEmailHeader, "dave@example.com")
+		req.Header.Set(UserGroupsHeader, "admins@example.com")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		id, ok := IdentityFromContext(reached.Context())
+		if !ok || id.Email != "dave@example.com" || id.Method != "header" {
+			t.Errorf("identity = %+v, %v", id, ok)
+		}
+	})
+
+	t.Run("headers ignored unless trusted", func(t *testing.T) {
+		untrusted := policy
+		untrusted.TrustHeaders = false
+		h := Authorize(untrusted, WithLogger(newTestLogger(t, &buf)))(http.NotFoundHandler())
+		req := httptest.NewRequest("GET", "/admin/users", nil)
+		req.Header.Set(UserEmailHeader, "mallory@example.com")
+		req.Header.Set(UserGroupsHeader, "admins@example.com")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", w.Code)
+		}
+	})
+}
+
+func TestAuthorizeIAPGroups(t *testing.T) {
+	const aud = "/projects/123/global/backendServices/456"
+	key := newECKey(t)
+	var buf bytes.Buffer
+	log := newTestLogger(t, &buf)
+
+	rules := []Rule{{Name: "admin", Pattern: "/admin/", Groups: []string{"admins@example.com"}}}
+	newHandler := func(trustHeaders bool) http.Handler {
+		iap := IAP(aud,
+			WithKeySource(StaticKeys{"iap-1": &key.PublicKey}),
+			WithClock(func() time.Time { return testNow }),
+			WithLogger(log),
+		)
+		authz := Authorize(Policy{Rules: rules, TrustHeaders: trustHeaders}, WithLogger(log))
+		return iap(authz(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, _ := IdentityFromContext(r.Context())
+			if id.Method != "iap" || !slices.Contains(id.Groups, "admins@example.com") {
+				t.Errorf("identity = %+v, want the IAP identity with merged groups", id)
+			}
+		})))
+	}
+
+	request := func(email, groups string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		req.Header.Set(IAPHeader, signES256(t, key, "iap-1", map[string]any{
+			"iss":   IAPIssuer,
+			"aud":   aud,
+			"sub":   "accounts.google.com:123",
+			"email": "alice@example.com",
+			"exp":   testNow.Add(time.Minute).Unix(),
+		}))
+		req.Header.Set(UserEmailHeader, email)
+		req.Header.Set(UserGroupsHeader, groups)
+		return req
+	}
+
+	tests := []struct {
+		name         string
+		trustHeaders bool
+		email        string
+		status       int
+	}{
+		{"groups merged for the same email", true, "alice@example.com", http.StatusOK},
+		{"email match ignores case", true, "Alice@Example.com", http.StatusOK},
+		{"groups of another email ignored", true, "mallory@example.com", http.StatusForbidden},
+		{"headers not trusted", false, "alice@example.com", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newHandler(tt.trustHeaders).ServeHTTP(w, request(tt.email, "admins@example.com"))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestAuthorizeInvalidPolicy(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), "/orders") {
+			t.Errorf("recover() = %v, want a panic naming the rule", r)
+		}
+	}()
+	Authorize(Policy{Rules: []Rule{
+		{Pattern: "/orders"},
+		{Pattern: "/orders"},
+	}})
+}
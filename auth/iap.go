@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const (
+	// IAPIssuer is the iss claim of IAP assertions
+	IAPIssuer = "https://cloud.google.com/iap"
+
+	// IAPJWKSURL serves the keys IAP signs assertions with
+	IAPJWKSURL = "https://www.gstatic.com/iap/verify/public_key-jwk"
+
+	// IAPHeader carries the signed assertion on requests IAP forwards
+	IAPHeader = "X-Goog-IAP-JWT-Assertion"
+)
+
+// iapKeys is the shared cache of IAP signing keys
+var iapKeys = sync.OnceValue(func() KeySource { return NewJWKS(IAPJWKSURL, nil) })
+
+// IAP returns middleware that verifies the Identity-Aware Proxy assertion on
+// every request and rejects the request with 401 when it is missing or
+// invalid. The assertion must be ES256-signed by IAP, unexpired and issued
+// for audience, which is "/projects/PROJECT_NUMBER/global/backendServices/SERVICE_ID"
+// for load balancers or "/projects/PROJECT_NUMBER/apps/PROJECT_ID" for App Engine.
+func IAP(audience string, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(iapKeys, opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := o.verifyIAP(r.Context(), r.Header.Get(IAPHeader), audience)
+			if err != nil {
+				o.rejectRequest(w, r, "iap", err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(authenticated(r.Context(), id)))
+		})
+	}
+}
+
+// verifyIAP verifies an IAP assertion and returns the user it names
+func (o options) verifyIAP(ctx context.Context, token, audience string) (Identity, error) {
+	c, err := verifyJWT(ctx, token, "ES256", o.keys)
+	if err != nil {
+		return Identity{}, err
+	}
+	if err := c.checkTimes(o.now()); err != nil {
+		return Identity{}, err
+	}
+	if err := c.checkIssuer(IAPIssuer); err != nil {
+		return Identity{}, err
+	}
+	if err := c.checkAudience(audience); err != nil {
+		return Identity{}, err
+	}
+	return Identity{Subject: c.Subject, Email: c.Email, Issuer: c.Issuer, Method: "iap"}, nil
+}
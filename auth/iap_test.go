@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIAP(t *testing.T) {
+	const aud = "/projects/123/global/backendServices/456"
+	key := newECKey(t)
+	keys := StaticKeys{"iap-1": &key.PublicKey}
+	var buf bytes.Buffer
+
+	assertion := func(overrides map[string]any) string {
+		claims := map[string]any{
+			"iss":   IAPIssuer,
+			"aud":   aud,
+			"sub":   "accounts.google.com:123",
+			"email": "alice@example.com",
+			"iat":   testNow.Add(-time.Minute).Unix(),
+			"exp":   testNow.Add(9 * time.Minute).Unix(),
+		}
+		for k, v := range overrides {
+			claims[k] = v
+		}
+		return signES256(t, key, "iap-1", claims)
+	}
+
+	var got Identity
+	var called bool
+	handler := IAP(aud,
+		WithKeySource(keys),
+		WithClock(func() time.Time { return testNow }),
+		WithLogger(newTestLogger(t, &buf)),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		got, _ = IdentityFromContext(r.Context())
+	}))
+
+	t.Run("valid assertion", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(IAPHeader, assertion(nil))
+		w, attrs := serveTraced(t, handler, req)
+
+		if w.Code != http.StatusOK || !called {
+			t.Fatalf("status = %d, called = %v", w.Code, called)
+		}
+		if got.Email != "alice@example.com" || got.Subject != "accounts.google.com:123" || got.Method != "iap" {
+			t.Errorf("identity = %+v", got)
+		}
+		if attrs["enduser.id"] != "alice@example.com" {
+			t.Errorf("enduser.id = %q", attrs["enduser.id"])
+		}
+	})
+
+	tests := []struct {
+		name  string
+		token string
+		want  Reason
+	}{
+		{"missing header", "", ReasonMissingToken},
+		{"wrong audience", assertion(map[string]any{"aud": "/projects/999/apps/other"}), ReasonInvalidAudience},
+		{"wrong issuer", assertion(map[string]any{"iss": "https://accounts.google.com"}), ReasonInvalidIssuer},
+		{"expired", assertion(map[string]any{"exp": testNow.Add(-time.Hour).Unix()}), ReasonExpired},
+		{"RS256", signRS256(t, newRSAKey(t), "iap-1", map[string]any{"aud": aud}), ReasonUnsupportedAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.token != "" {
+				req.Header.Set(IAPHeader, tt.token)
+			}
+			w, attrs := serveTraced(t, handler, req)
+
+			if w.Code != http.StatusUnauthorized || called {
+				t.Errorf("status = %d, called = %v, want 401 without calling next", w.Code, called)
+			}
+			if attrs["auth.failure_reason"] != string(tt.want) {
+				t.Errorf("auth.failure_reason = %q, want %q", attrs["auth.failure_reason"], tt.want)
+			}
+		})
+	}
+}
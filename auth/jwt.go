@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is the leeway allowed when checking exp and iat
+const clockSkew = 30 * time.Second
+
+// header is the JOSE header of a signed JWT
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// claims are the JWT claims checked by the verifiers
+type claims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	Expiry        int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+}
+
+// audience accepts the single string or array forms of the aud claim
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("aud must be a string or array of strings")
+	}
+	*a = list
+	return nil
+}
+
+// verifyJWT checks the signature of a compact JWS with the key its kid names
+// and returns the claims. alg is the only algorithm accepted.
+func verifyJWT(ctx context.Context, token, alg string, keys KeySource) (*claims, error) {
+	if token == "" {
+		return nil, reject(ReasonMissingToken, nil)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, reject(ReasonMalformedToken, errors.New("token must have three parts"))
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, reject(ReasonMalformedToken, fmt.Errorf("header: %w", err))
+	}
+	if h.Alg != alg {
+		return nil, reject(ReasonUnsupportedAlgorithm, fmt.Errorf("alg %q, want %s", h.Alg, alg))
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, reject(ReasonMalformedToken, fmt.Errorf("signature: %w", err))
+	}
+
+	key, err := keys.PublicKey(ctx, h.Kid)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, reject(ReasonUnknownKey, fmt.Errorf("kid %q", h.Kid))
+	}
+	if err != nil {
+		return nil, reject(ReasonKeysUnavailable, err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := verifySignature(alg, key, digest[:], sig); err != nil {
+		return nil, reject(ReasonInvalidSignature, err)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, reject(ReasonMalformedToken, fmt.Errorf("claims: %w", err))
+	}
+	return &c, nil
+}
+
+// verifySignature checks sig over digest for ES256 or RS256
+func verifySignature(alg string, key crypto.PublicKey, digest, sig []byte) error {
+	switch alg {
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key is %T, want *ecdsa.PublicKey", key)
+		}
+		// JWS encodes the signature as fixed-width r || s
+		if len(sig) != 64 {
+			return fmt.Errorf("signature is %d bytes, want 64", len(sig))
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key is %T, want *rsa.PublicKey", key)
+		}
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, sig)
+	default:
+		return fmt.Errorf("unsupported alg %q", alg)
+	}
+}
+
+// decodeSegment decodes a base64url JSON segment into v
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// checkTimes rejects expired tokens and tokens issued in the future
+func (c *claims) checkTimes(now time.Time) error {
+	if c.Expiry == 0 || now.After(time.Unix(c.Expiry, 0).Add(clockSkew)) {
+		return reject(ReasonExpired, nil)
+	}
+	if c.IssuedAt != 0 && now.Add(clockSkew).Before(time.Unix(c.IssuedAt, 0)) {
+		return reject(ReasonIssuedInFuture, nil)
+	}
+	return nil
+}
+
+// checkIssuer rejects tokens from issuers other than those given
+func (c *claims) checkIssuer(issuers ...string) error {
+	if !slices.Contains(issuers, c.Issuer) {
+		return reject(ReasonInvalidIssuer, fmt.Errorf("iss %q", c.Issuer))
+	}
+	return nil
+}
+
+// checkAudience rejects tokens not intended for any of the given audiences
+func (c *claims) checkAudience(audiences ...string) error {
+	for _, aud := range c.Audience {
+		if slices.Contains(audiences, aud) {
+			return nil
+		}
+	}
+	return reject(ReasonInvalidAudience, fmt.Errorf("aud %q", []string(c.Audience)))
+}
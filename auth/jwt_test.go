@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudienceUnmarshal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`"a"`, []string{"a"}},
+		{`["a","b"]`, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		var a audience
+		if err := json.Unmarshal([]byte(tt.in), &a); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", tt.in, err)
+		}
+		if strings.Join(a, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, a, tt.want)
+		}
+	}
+	var a audience
+	if err := json.Unmarshal([]byte(`42`), &a); err == nil {
+		t.Error("Unmarshal(42) error = nil")
+	}
+}
+
+func TestVerifyJWT(t *testing.T) {
+	ctx := context.Background()
+	ecKey := newECKey(t)
+	rsaKey := newRSAKey(t)
+	keys := StaticKeys{"ec": &ecKey.PublicKey, "rsa": &rsaKey.PublicKey}
+	claims := map[string]any{"sub": "123", "aud": "x"}
+
+	t.Run("ES256", func(t *testing.T) {
+		c, err := verifyJWT(ctx, signES256(t, ecKey, "ec", claims), "ES256", keys)
+		if err != nil {
+			t.Fatalf("verifyJWT() error = %v", err)
+		}
+		if c.Subject != "123" {
+			t.Errorf("Subject = %q", c.Subject)
+		}
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		if _, err := verifyJWT(ctx, signRS256(t, rsaKey, "rsa", claims), "RS256", keys); err != nil {
+			t.Fatalf("verifyJWT() error = %v", err)
+		}
+	})
+
+	otherKey := newECKey(t)
+	valid := signES256(t, ecKey, "ec", claims)
+	parts := strings.Split(valid, ".")
+	tampered := strings.Split(encodeSegments(t, nil, map[string]any{"sub": "456"}), ".")[1]
+	tests := []struct {
+		name  string
+		token string
+		alg   string
+		want  Reason
+	}{
+		{"missing", "", "ES256", ReasonMissingToken},
+		{"two parts", parts[0] + "." + parts[1], "ES256", ReasonMalformedToken},
+		{"bad header", "!!." + parts[1] + "." + parts[2], "ES256", ReasonMalformedToken},
+		{"wrong alg", signRS256(t, rsaKey, "rsa", claims), "ES256", ReasonUnsupportedAlgorithm},
+		{"unknown kid", signES256(t, ecKey, "nope", claims), "ES256", ReasonUnknownKey},
+		{"wrong key", signES256(t, otherKey, "ec", claims), "ES256", ReasonInvalidSignature},
+		{"tampered claims", parts[0] + "." + tampered + "." + parts[2], "ES256", ReasonInvalidSignature},
+		{"key type mismatch", signES256(t, ecKey, "rsa", claims), "ES256", ReasonInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifyJWT(ctx, tt.token, tt.alg, keys)
+			if got := ReasonOf(err); got != tt.want {
+				t.Errorf("verifyJWT() reason = %q (%v), want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimsChecks(t *testing.T) {
+	c := claims{
+		Issuer:   "issuer",
+		Audience: audience{"a", "b"},
+		Expiry:   testNow.Add(time.Minute).Unix(),
+		IssuedAt: testNow.Add(-time.Minute).Unix(),
+	}
+
+	if err := c.checkTimes(testNow); err != nil {
+		t.Errorf("checkTimes() error = %v", err)
+	}
+	if err := c.checkTimes(testNow.Add(time.Minute + clockSkew/2)); err != nil {
+		t.Errorf("checkTimes() within skew error = %v", err)
+	}
+	if got := ReasonOf(c.checkTimes(testNow.Add(2 * time.Minute))); got != ReasonExpired {
+		t.Errorf("checkTimes() after exp = %q, want expired", got)
+	}
+	if got := ReasonOf(c.checkTimes(testNow.Add(-2 * time.Minute))); got != ReasonIssuedInFuture {
+		t.Errorf("checkTimes() before iat = %q, want issued_in_future", got)
+	}
+	if got := ReasonOf((&claims{}).checkTimes(testNow)); got != ReasonExpired {
+		t.Errorf("checkTimes() without exp = %q, want expired", got)
+	}
+
+	if err := c.checkIssuer("other", "issuer"); err != nil {
+		t.Errorf("checkIssuer() error = %v", err)
+	}
+	if got := ReasonOf(c.checkIssuer("other")); got != ReasonInvalidIssuer {
+		t.Errorf("checkIssuer() = %q", got)
+	}
+	if err := c.checkAudience("b"); err != nil {
+		t.Errorf("checkAudience() error = %v", err)
+	}
+	if got := ReasonOf(c.checkAudience("c")); got != ReasonInvalidAudience {
+		t.Errorf("checkAudience() = %q", got)
+	}
+}
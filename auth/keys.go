@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultJWKSTTL is how long keys are cached without a Cache-Control max-age
+	defaultJWKSTTL = time.Hour
+
+	// minJWKSRefresh limits refetches triggered by unknown key IDs or failures
+	minJWKSRefresh = time.Minute
+
+	// maxJWKSSize bounds the JWKS response read
+	maxJWKSSize = 1 << 20
+)
+
+// ErrKeyNotFound is returned by a KeySource that has no key for a key ID
+var ErrKeyNotFound = errors.New("auth: key not found")
+
+// KeySource returns the public key a token's kid header names
+type KeySource interface {
+	PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// StaticKeys is a fixed KeySource, for tests and pinned keys
+type StaticKeys map[string]crypto.PublicKey
+
+// PublicKey implements KeySource
+func (k StaticKeys) PublicKey(_ context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// JWKS is a KeySource backed by a JSON Web Key Set URL. Keys are cached for
+// the response's Cache-Control max-age (default one hour) and refetched early
+// when a token names an unknown key. Fetches, including retries after a
+// failure, happen at most once a minute; one caller fetches while the others
+// wait, and cached keys keep verifying tokens while the endpoint is down.
+type JWKS struct {
+	url    string
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	expires   time.Time
+	lastFetch time.Time
+	lastErr   error         // error of the last fetch, nil after a success
+	fetching  chan struct{} // closed when the fetch in flight finishes
+}
+
+// NewJWKS returns a JWKS for url. A nil client uses http.DefaultClient.
+func NewJWKS(url string, client *http.Client) *JWKS {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &JWKS{url: url, client: client, now: time.Now}
+}
+
+// PublicKey implements KeySource
+func (j *JWKS) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	for {
+		j.mu.Lock()
+		now := j.now()
+		key, ok := j.keys[kid]
+		if ok && now.Before(j.expires) {
+			j.mu.Unlock()
+			return key, nil
+		}
+
+		// Back off after a recent fetch, serving a stale key if there is one
+		if !j.lastFetch.IsZero() && now.Sub(j.lastFetch) < minJWKSRefresh {
+			err := j.lastErr
+			j.mu.Unlock()
+			if ok {
+				return key, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return nil, ErrKeyNotFound
+		}
+
+		// Another caller is fetching: use the stale key or wait for the result
+		if wait := j.fetching; wait != nil {
+			j.mu.Unlock()
+			if ok {
+				return key, nil
+			}
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		done := make(chan struct{})
+		j.fetching = done
+		j.mu.Unlock()
+
+		keys, ttl, err := j.fetch(ctx)
+
+		j.mu.Lock()
+		j.fetching = nil
+		// A caller giving up is not a failed attempt and must not delay others
+		if err == nil || ctx.Err() == nil {
+			j.lastFetch = now
+			j.lastErr = err
+		}
+		if err == nil {
+			j.keys = keys
+			j.expires = now.Add(ttl)
+		}
+		key, ok = j.keys[kid]
+		j.mu.Unlock()
+		close(done)
+
+		switch {
+		case ok:
+			return key, nil
+		case err != nil:
+			return nil, err
+		default:
+			return nil, ErrKeyNotFound
+		}
+	}
+}
+
+// fetch downloads the key set and returns it with its cache lifetime
+func (j *JWKS) fetch(ctx context.Context) (map[string]crypto.PublicKey, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("failed to fetch JWKS: %s returned %s", j.url, resp.Status)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(nil, resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			// Skip keys of types we do not verify with
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, cacheTTL(resp.Header.Get("Cache-Control")), nil
+}
+
+// cacheTTL returns the max-age of a Cache-Control header, or the default
+func cacheTTL(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return defaultJWKSTTL
+}
+
+// jwk is a JSON Web Key holding an EC P-256 or RSA public key
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// publicKey decodes the key material
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 coordinates")
+		}
+		return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,283 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ecJWK encodes an EC public key as a JWK
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	b, err := key.Bytes()
+	if err != nil {
+		panic(err)
+	}
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256", "alg": "ES256",
+		"x": base64.RawURLEncoding.EncodeToString(b[1:33]),
+		"y": base64.RawURLEncoding.EncodeToString(b[33:]),
+	}
+}
+
+// rsaJWK encodes an RSA public key as a JWK
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "alg": "RS256",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// jwksServer serves the keys returned by keys and counts fetches
+func jwksServer(t *testing.T, cacheControl string, keys func() []map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys()})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &fetches
+}
+
+func TestStaticKeys(t *testing.T) {
+	key := newECKey(t)
+	keys := StaticKeys{"a": &key.PublicKey}
+	if got, err := keys.PublicKey(context.Background(), "a"); err != nil || got != &key.PublicKey {
+		t.Errorf("PublicKey(a) = %v, %v", got, err)
+	}
+	if _, err := keys.PublicKey(context.Background(), "b"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("PublicKey(b) error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	ctx := context.Background()
+	ecKey := newECKey(t)
+	rsaKey := newRSAKey(t)
+
+	t.Run("parses EC and RSA keys", func(t *testing.T) {
+		srv, _ := jwksServer(t, "", func() []map[string]string {
+			return []map[string]string{
+				ecJWK("ec", &ecKey.PublicKey),
+				rsaJWK("rsa", &rsaKey.PublicKey),
+				{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"},
+			}
+		})
+		jwks := NewJWKS(srv.URL, srv.Client())
+
+		got, err := jwks.PublicKey(ctx, "ec")
+		if err != nil {
+			t.Fatalf("PublicKey(ec) error = %v", err)
+		}
+		if !ecKey.PublicKey.Equal(got) {
+			t.Error("EC key does not match")
+		}
+		got, err = jwks.PublicKey(ctx, "rsa")
+		if err != nil {
+			t.Fatalf("PublicKey(rsa) error = %v", err)
+		}
+		if !rsaKey.PublicKey.Equal(got) {
+			t.Error("RSA key does not match")
+		}
+		if _, err := jwks.PublicKey(ctx, "secret"); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("PublicKey(secret) error = %v, want ErrKeyNotFound", err)
+		}
+	})
+
+	t.Run("caches for max-age", func(t *testing.T) {
+		srv, fetches := jwksServer(t, "public, max-age=120", func() []map[string]string {
+			return []map[string]string{ecJWK("ec", &ecKey.PublicKey)}
+		})
+		now := testNow
+		jwks := NewJWKS(srv.URL, srv.Client())
+		jwks.now = func() time.Time { return now }
+
+		for range 3 {
+			if _, err := jwks.PublicKey(ctx, "ec"); err != nil {
+				t.Fatalf("PublicKey() error = %v", err)
+			}
+		}
+		if n := fetches.Load(); n != 1 {
+			t.Errorf("fetches = %d, want 1 while cached", n)
+		}
+
+		now = now.Add(121 * time.Second)
+		if _, err := jwks.PublicKey(ctx, "ec"); err != nil {
+			t.Fatalf("PublicKey() error = %v", err)
+		}
+		if n := fetches.Load(); n != 2 {
+			t.Errorf("fetches = %d, want 2 after max-age", n)
+		}
+	})
+
+	t.Run("refetches unknown keys at most once a minute", func(t *testing.T) {
+		rotated := false
+		srv, fetches := jwksServer(t, "max-age=3600", func() []map[string]string {
+			keys := []map[string]string{ecJWK("old", &ecKey.PublicKey)}
+			if rotated {
+				keys = append(keys, ecJWK("new", &ecKey.PublicKey))
+			}
+			return keys
+		})
+		now := testNow
+		jwks := NewJWKS(srv.URL, srv.Client())
+		jwks.now = func() time.Time { return now }
+
+		if _, err := jwks.PublicKey(ctx, "old"); err != nil {
+			t.Fatalf("PublicKey(old) error = %v", err)
+		}
+		rotated = true
+		if _, err := jwks.PublicKey(ctx, "new"); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("PublicKey(new) within a minute error = %v, want ErrKeyNotFound", err)
+		}
+		if n := fetches.Load(); n != 1 {
+			t.Errorf("fetches = %d, want 1", n)
+		}
+
+		now = now.Add(minJWKSRefresh)
+		if _, err := jwks.PublicKey(ctx, "new"); err != nil {
+			t.Errorf("PublicKey(new) after rotation error = %v", err)
+		}
+		if n := fetches.Load(); n != 2 {
+			t.Errorf("fetches = %d, want 2", n)
+		}
+	})
+
+	t.Run("serves cached keys while the endpoint is down", func(t *testing.T) {
+		var down atomic.Bool
+		var fetches atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches.Add(1)
+			if down.Load() {
+				http.Error(w, "down", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Cache-Control", "max-age=120")
+			json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{ecJWK("ec", &ecKey.PublicKey)}})
+		}))
+		defer srv.Close()
+		now := testNow
+		jwks := NewJWKS(srv.URL, srv.Client())
+		jwks.now = func() time.Time { return now }
+
+		if _, err := jwks.PublicKey(ctx, "ec"); err != nil {
+			t.Fatalf("PublicKey() error = %v", err)
+		}
+		down.Store(true)
+		now = now.Add(121 * time.Second)
+
+		for range 3 {
+			if _, err := jwks.PublicKey(ctx, "ec"); err != nil {
+				t.Errorf("PublicKey() with a failed refresh error = %v, want the cached key", err)
+			}
+		}
+		if n := fetches.Load(); n != 2 {
+			t.Errorf("fetches = %d, want 2 with retries backed off", n)
+		}
+		if _, err := jwks.PublicKey(ctx, "other"); err == nil || errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("PublicKey(other) error = %v, want the fetch error", err)
+		}
+
+		now = now.Add(minJWKSRefresh)
+		down.Store(false)
+		if _, err := jwks.PublicKey(ctx, "ec"); err != nil {
+			t.Errorf("PublicKey() after recovery error = %v", err)
+		}
+		if n := fetches.Load(); n != 3 {
+			t.Errorf("fetches = %d, want 3 after the backoff", n)
+		}
+	})
+
+	t.Run("one fetch at a time without blocking cached keys", func(t *testing.T) {
+		release := make(chan struct{})
+		var fetches atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fetches.Add(1) > 1 {
+				<-release
+			}
+			keys := []map[string]string{ecJWK("old", &ecKey.PublicKey)}
+			if fetches.Load() > 1 {
+				keys = append(keys, ecJWK("new", &ecKey.PublicKey))
+			}
+			json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+		}))
+		defer srv.Close()
+		defer close(release)
+		now := testNow
+		jwks := NewJWKS(srv.URL, srv.Client())
+		jwks.now = func() time.Time { return now }
+
+		if _, err := jwks.PublicKey(ctx, "old"); err != nil {
+			t.Fatalf("PublicKey(old) error = %v", err)
+		}
+		now = now.Add(minJWKSRefresh)
+
+		results := make(chan error, 2)
+		for range 2 {
+			go func() {
+				_, err := jwks.PublicKey(ctx, "new")
+				results <- err
+			}()
+		}
+		for fetches.Load() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+
+		// The slow fetch must not hold up tokens signed with a cached key
+		if _, err := jwks.PublicKey(ctx, "old"); err != nil {
+			t.Errorf("PublicKey(old) during a fetch error = %v", err)
+		}
+
+		release <- struct{}{}
+		for range 2 {
+			if err := <-results; err != nil {
+				t.Errorf("PublicKey(new) error = %v", err)
+			}
+		}
+		if n := fetches.Load(); n != 2 {
+			t.Errorf("fetches = %d, want 2 with concurrent callers sharing one", n)
+		}
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "down", http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		_, err := NewJWKS(srv.URL, srv.Client()).PublicKey(ctx, "ec")
+		if err == nil || errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("PublicKey() error = %v, want a fetch error", err)
+		}
+	})
+}
+
+func TestCacheTTL(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", defaultJWKSTTL},
+		{"public, max-age=300, must-revalidate", 300 * time.Second},
+		{"Max-Age=60", 60 * time.Second},
+		{"max-age=abc", defaultJWKSTTL},
+		{"no-cache", defaultJWKSTTL},
+	}
+	for _, tt := range tests {
+		if got := cacheTTL(tt.header); got != tt.want {
+			t.Errorf("cacheTTL(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
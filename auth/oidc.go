@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// GoogleJWKSURL serves the keys Google signs OIDC ID tokens with
+const GoogleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+// GoogleIssuers are the iss claims of Google-signed ID tokens
+var GoogleIssuers = []string{"https://accounts.google.com", "accounts.google.com"}
+
+// googleKeys is the shared cache of Google OIDC signing keys
+var googleKeys = sync.OnceValue(func() KeySource { return NewJWKS(GoogleJWKSURL, nil) })
+
+// GoogleOIDC returns middleware that verifies the Google-signed OIDC bearer
+// token that Cloud Run, Pub/Sub push, Cloud Tasks and Cloud Scheduler send
+// when invoking a service as a service account. The token must be RS256-signed
+// by Google, unexpired, issued for one of audiences and carry a verified email.
+// When allowedEmails is non-empty the email must also be one of them.
+func GoogleOIDC(audiences, allowedEmails []string, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(googleKeys, opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := o.verifyGoogleOIDC(r.Context(), bearerToken(r), audiences, allowedEmails)
+			if err != nil {
+				o.rejectRequest(w, r, "oidc", err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(authenticated(r.Context(), id)))
+		})
+	}
+}
+
+// verifyGoogleOIDC verifies a Google ID token and returns the service account it names
+func (o options) verifyGoogleOIDC(ctx context.Context, token string, audiences, allowedEmails []string) (Identity, error) {
+	c, err := verifyJWT(ctx, token, "RS256", o.keys)
+	if err != nil {
+		return Identity{}, err
+	}
+	if err := c.checkTimes(o.now()); err != nil {
+		return Identity{}, err
+	}
+	if err := c.checkIssuer(GoogleIssuers...); err != nil {
+		return Identity{}, err
+	}
+	if err := c.checkAudience(audiences...); err != nil {
+		return Identity{}, err
+	}
+	if c.Email == "" || !c.EmailVerified {
+		return Identity{}, reject(ReasonEmailNotVerified, fmt.Errorf("email %q", c.Email))
+	}
+	if len(allowedEmails) > 0 && !slices.Contains(allowedEmails, c.Email) {
+		return Identity{}, reject(ReasonEmailNotAllowed, fmt.Errorf("email %q", c.Email))
+	}
+	return Identity{Subject: c.Subject, Email: c.Email, Issuer: c.Issuer, Method: "oidc"}, nil
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header, or ""
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoogleOIDC(t *testing.T) {
+	const (
+		aud     = "https://worker-abc.a.run.app"
+		invoker = "scheduler@my-project.iam.gserviceaccount.com"
+	)
+	key := newRSAKey(t)
+	keys := StaticKeys{"google-1": &key.PublicKey}
+	var buf bytes.Buffer
+
+	token := func(overrides map[string]any) string {
+		claims := map[string]any{
+			"iss":            "https://accounts.google.com",
+			"aud":            aud,
+			"sub":            "1234567890",
+			"email":          invoker,
+			"email_verified": true,
+			"iat":            testNow.Add(-time.Minute).Unix(),
+			"exp":            testNow.Add(59 * time.Minute).Unix(),
+		}
+		for k, v := range overrides {
+			claims[k] = v
+		}
+		return signRS256(t, key, "google-1", claims)
+	}
+
+	newHandler := func(allowed []string) (http.Handler, *Identity, *bool) {
+		var got Identity
+		var called bool
+		return GoogleOIDC([]string{aud, "other-audience"}, allowed,
+			WithKeySource(keys),
+			WithClock(func() time.Time { return testNow }),
+			WithLogger(newTestLogger(t, &buf)),
+		)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			got, _ = IdentityFromContext(r.Context())
+		})), &got, &called
+	}
+	handler, got, called := newHandler([]string{invoker})
+
+	request := func(authorization string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/tasks/run", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return req
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		w, attrs := serveTraced(t, handler, request("Bearer "+token(nil)))
+		if w.Code != http.StatusOK || !*called {
+			t.Fatalf("status = %d, called = %v", w.Code, *called)
+		}
+		if got.Email != invoker || got.Method != "oidc" || got.Issuer != "https://accounts.google.com" {
+			t.Errorf("identity = %+v", *got)
+		}
+		if attrs["enduser.id"] != invoker || attrs["auth.method"] != "oidc" {
+			t.Errorf("span attributes = %v", attrs)
+		}
+	})
+
+	t.Run("issuer without scheme", func(t *testing.T) {
+		w, _ := serveTraced(t, handler, request("bearer "+token(map[string]any{"iss": "accounts.google.com"})))
+		if w.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200", w.Code)
+		}
+	})
+
+	t.Run("any verified email when no allowlist", func(t *testing.T) {
+		open, _, _ := newHandler(nil)
+		w, _ := serveTraced(t, open, request("Bearer "+token(map[string]any{"email": "other@example.iam.gserviceaccount.com"})))
+		if w.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200", w.Code)
+		}
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          Reason
+	}{
+		{"missing header", "", ReasonMissingToken},
+		{"basic auth", "Basic dXNlcjpwYXNz", ReasonMissingToken},
+		{"wrong audience", "Bearer " + token(map[string]any{"aud": "https://other.a.run.app"}), ReasonInvalidAudience},
+		{"wrong issuer", "Bearer " + token(map[string]any{"iss": IAPIssuer}), ReasonInvalidIssuer},
+		{"expired", "Bearer " + token(map[string]any{"exp": testNow.Add(-time.Hour).Unix()}), ReasonExpired},
+		{"unverified email", "Bearer " + token(map[string]any{"email_verified": false}), ReasonEmailNotVerified},
+		{"no email", "Bearer " + token(map[string]any{"email": ""}), ReasonEmailNotVerified},
+		{"email not allowed", "Bearer " + token(map[string]any{"email": "intruder@evil.iam.gserviceaccount.com"}), ReasonEmailNotAllowed},
+		{"ES256", "Bearer " + signES256(t, newECKey(t), "google-1", map[string]any{"aud": aud}), ReasonUnsupportedAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*called = false
+			buf.Reset()
+			w, attrs := serveTraced(t, handler, request(tt.authorization))
+
+			if w.Code != http.StatusUnauthorized || *called {
+				t.Errorf("status = %d, called = %v, want 401 without calling next", w.Code, *called)
+			}
+			if attrs["auth.failure_reason"] != string(tt.want) {
+				t.Errorf("auth.failure_reason = %q, want %q", attrs["auth.failure_reason"], tt.want)
+			}
+			if !strings.Contains(buf.String(), string(tt.want)) {
+				t.Errorf("log = %s, want the reason", buf.String())
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := map[string]string{
+		"":             "",
+		"Bearer abc":   "abc",
+		"bearer  abc ": "abc",
+		"Basic abc":    "",
+		"Bearerabc":    "",
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", header)
+		if got := bearerToken(req); got != want {
+			t.Errorf("bearerToken(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
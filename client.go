@@ -3,11 +3,19 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/sirhco/go-gcp-middleware/logger"
 	"github.com/sirhco/go-gcp-middleware/telemetry"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // Config holds the complete middleware configuration
@@ -27,13 +35,61 @@ type Config struct {
 	LogLevel      logger.Level
 	PrettyLog     bool // Pretty-print console logs
 
+	// EnableErrorReporting formats error logs for Cloud Error Reporting
+	EnableErrorReporting bool
+
+	// Redact scrubs sensitive data from logs and span attributes (nil disables)
+	Redact *logger.RedactConfig
+
+	// RateLimit suppresses floods of identical log lines (nil disables)
+	RateLimit *logger.RateLimitConfig
+
+	// AsyncLog buffers log writes on a background goroutine (nil writes synchronously)
+	AsyncLog *logger.AsyncConfig
+
+	// CloudLogging writes logs through the Cloud Logging API with real log names (nil disables)
+	CloudLogging *logger.CloudLoggingConfig
+
+	// SpanEvents mirrors log records onto the active span as "log" events (nil disables)
+	SpanEvents *logger.SpanEventsConfig
+
+	// Audit configures the audit log written by Logger.Audit (nil uses defaults)
+	Audit *logger.AuditConfig
+
+	// Per-sink level and format overrides, and additional slog.Handler sinks
+	ConsoleSink logger.SinkOptions
+	GCPSink     logger.SinkOptions
+	LogHandlers []slog.Handler
+
 	// Telemetry configuration
 	EnableTracing bool    // Enable OpenTelemetry tracing
 	EnableMetrics bool    // Enable metrics (future)
 	TraceRatio    float64 // Sampling ratio (0.0 to 1.0)
 
+	// TraceExporter selects the span destination (default: Cloud Trace)
+	TraceExporter telemetry.Exporter
+
+	// ParentBasedSampling follows the caller's sampling decision and applies
+	// TraceRatio only to new traces
+	ParentBasedSampling bool
+
+	// Baggage trusts the allowlisted baggage members from callers and copies
+	// them onto spans and logs (nil disables)
+	Baggage *telemetry.BaggageConfig
+
+	// Telemetry overrides for advanced setups; nil keeps the built-in behavior
+	SpanExporter sdktrace.SpanExporter         // Replaces the Cloud Trace or stdout exporter
+	Sampler      sdktrace.Sampler              // Replaces the TraceRatio sampler
+	Resource     *resource.Resource            // Replaces the detected service and GCP resource
+	Propagator   propagation.TextMapPropagator // Replaces the trace context and baggage propagator
+	IDGenerator  sdktrace.IDGenerator          // Replaces the random trace and span ID generator
+
 	// Custom attributes
 	Attributes map[string]string
+
+	// Isolated gives the client its own logger and tracer provider without
+	// installing them as the package, slog and OpenTelemetry globals
+	Isolated bool
 }
 
 // SetDefaults sets reasonable defaults for the configuration
@@ -77,6 +133,9 @@ type Client struct {
 	config    Config
 	logger    *logger.Logger
 	telemetry *telemetry.Provider
+
+	// unregisterExit removes the Fatal exit hook registered by NewClient
+	unregisterExit func()
 }
 
 // NewClient creates and initializes a new middleware client
@@ -100,6 +159,17 @@ func NewClient(ctx context.Context, config Config) (*Client, error) {
 		EnableGCP:      config.EnableGCP,
 		Level:          config.LogLevel,
 		Pretty:         config.PrettyLog,
+
+		EnableErrorReporting: config.EnableErrorReporting,
+		Redact:               config.Redact,
+		RateLimit:            config.RateLimit,
+		Async:                config.AsyncLog,
+		CloudLogging:         config.CloudLogging,
+		Console:              config.ConsoleSink,
+		GCP:                  config.GCPSink,
+		Handlers:             config.LogHandlers,
+		SpanEvents:           config.SpanEvents,
+		Audit:                config.Audit,
 	}
 
 	log, err := logger.NewLogger(ctx, loggerConfig)
@@ -108,11 +178,6 @@ func NewClient(ctx context.Context, config Config) (*Client, error) {
 	}
 	client.logger = log
 
-	// Initialize global logger
-	if err := logger.InitGlobal(ctx, loggerConfig); err != nil {
-		return nil, fmt.Errorf("failed to initialize global logger: %w", err)
-	}
-
 	// Initialize telemetry if enabled
 	if config.EnableTracing {
 		telemetryConfig := telemetry.Config{
@@ -123,21 +188,42 @@ func NewClient(ctx context.Context, config Config) (*Client, error) {
 			EnableTracing:  config.EnableTracing,
 			EnableMetrics:  config.EnableMetrics,
 			TraceRatio:     config.TraceRatio,
+			Exporter:       config.TraceExporter,
+			ParentBased:    config.ParentBasedSampling,
 			Attributes:     config.Attributes,
+			Isolated:       config.Isolated,
+			Baggage:        config.Baggage,
+			SpanExporter:   config.SpanExporter,
+			Sampler:        config.Sampler,
+			Resource:       config.Resource,
+			Propagator:     config.Propagator,
+			IDGenerator:    config.IDGenerator,
+		}
+		if config.Redact != nil {
+			telemetryConfig.Redactor = logger.NewRedactor(*config.Redact)
 		}
 
 		provider, err := telemetry.NewProvider(ctx, telemetryConfig)
 		if err != nil {
+			// Stop the logger's workers and Cloud Logging client, which no
+			// caller can reach once NewClient fails
+			_ = log.Shutdown(ctx)
 			return nil, fmt.Errorf("failed to initialize telemetry: %w", err)
 		}
 		client.telemetry = provider
 
-		// Initialize global telemetry
-		if err := telemetry.InitGlobal(ctx, telemetryConfig); err != nil {
-			return nil, fmt.Errorf("failed to initialize global telemetry: %w", err)
+		// Share the provider globally, so injected exporters are not used twice
+		if !config.Isolated {
+			telemetry.SetGlobal(provider)
 		}
 	}
 
+	// Share the logger globally, so audit records form one chain and sinks,
+	// queues and rate limits exist once per client
+	if !config.Isolated {
+		logger.SetGlobal(log)
+	}
+
 	client.logger.InfoContext(ctx, "Middleware client initialized",
 		"service", config.ServiceName,
 		"version", config.ServiceVersion,
@@ -145,9 +231,21 @@ func NewClient(ctx context.Context, config Config) (*Client, error) {
 		"tracing_enabled", config.EnableTracing,
 	)
 
+	// Flush spans and drain logs when logger.Fatal exits the process
+	client.unregisterExit = logger.RegisterExitHook(client.Shutdown)
+
 	return client, nil
 }
 
+// ResetGlobals clears the global logger and telemetry provider installed by
+// NewClient, so the next non-isolated client installs its own. It does not
+// shut anything down; call Client.Shutdown first. Intended for tests that
+// create several clients; parallel tests should use Isolated clients instead.
+func ResetGlobals() {
+	logger.ResetGlobal()
+	telemetry.ResetGlobal()
+}
+
 // Logger returns the logger instance
 func (c *Client) Logger() *logger.Logger {
 	return c.logger
@@ -167,6 +265,10 @@ func (c *Client) Config() Config {
 func (c *Client) Shutdown(ctx context.Context) error {
 	c.logger.InfoContext(ctx, "Shutting down middleware client")
 
+	if c.unregisterExit != nil {
+		c.unregisterExit()
+	}
+
 	// Shutdown telemetry first to flush traces
 	if c.telemetry != nil {
 		if err := c.telemetry.Shutdown(ctx); err != nil {
@@ -175,23 +277,101 @@ func (c *Client) Shutdown(ctx context.Context) error {
 		}
 	}
 
-	// Shutdown logger
-	if err := c.logger.Close(); err != nil {
+	// Drain buffered logs; this is also the global logger unless isolated
+	if err := c.logger.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to close logger: %w", err)
 	}
 
 	return nil
 }
 
+// tracerProvider returns the client's tracer provider. Without tracing it is
+// the global provider, or a no-op for isolated clients.
+func (c *Client) tracerProvider() trace.TracerProvider {
+	switch {
+	case c.telemetry != nil:
+		return c.telemetry.TracerProvider()
+	case c.config.Isolated:
+		return noop.NewTracerProvider()
+	default:
+		return otel.GetTracerProvider()
+	}
+}
+
+// propagator returns the configured propagator, else the global one, or a
+// private one for isolated clients
+func (c *Client) propagator() propagation.TextMapPropagator {
+	switch {
+	case c.config.Propagator != nil:
+		return c.config.Propagator
+	case c.config.Isolated:
+		return telemetry.NewPropagator()
+	default:
+		return otel.GetTextMapPropagator()
+	}
+}
+
+// loggerFunc returns the client's logger, for the shared middleware implementations
+func (c *Client) loggerFunc() *logger.Logger {
+	return c.logger
+}
+
+// Logging is the Logging middleware writing to the client's logger
+func (c *Client) Logging(next http.Handler) http.Handler {
+	return logRequests(next, c.loggerFunc)
+}
+
+// Recovery is the Recovery middleware writing to the client's logger
+func (c *Client) Recovery(next http.Handler) http.Handler {
+	return recoverPanics(next, c.loggerFunc)
+}
+
+// CloudTasks is the CloudTasks middleware with WriteTaskResult logging to
+// the client's logger
+func (c *Client) CloudTasks(maxAttempts int) func(http.Handler) http.Handler {
+	return CloudTasks(maxAttempts, WithTaskLogger(c.logger))
+}
+
+// OTelHTTP is the OTelHTTP middleware using the client's tracer provider
+func (c *Client) OTelHTTP(operation string) func(http.Handler) http.Handler {
+	return otelHTTP(operation, c.tracerProvider(), c.propagator())
+}
+
+// baggage applies the Baggage middleware when the client configures it
+func (c *Client) baggage(next http.Handler) http.Handler {
+	if c.config.Baggage == nil {
+		return next
+	}
+	return Baggage(*c.config.Baggage)(next)
+}
+
+// CustomSpan is the CustomSpan middleware using the client's tracer
+func (c *Client) CustomSpan(spanName string) func(http.Handler) http.Handler {
+	tracer := c.tracerProvider().Tracer(c.config.ServiceName)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := tracer.Start(r.Context(), spanName,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(customSpanAttrs(r)...),
+			)
+			defer span.End()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // HTTPHandler creates a fully instrumented HTTP handler with all middleware
 func (c *Client) HTTPHandler(handler http.HandlerFunc, operationName string) http.Handler {
 	// Build middleware chain from outside to inside:
-	// OTelHTTP -> RequestID -> Logging -> Recovery -> CORS -> Handler
-	return OTelHTTP(operationName)(
-		RequestID(
-			Logging(
-				Recovery(
-					CORS(handler),
+	// OTelHTTP -> Baggage -> RequestID -> Logging -> Recovery -> CORS -> Handler
+	return c.OTelHTTP(operationName)(
+		c.baggage(
+			RequestID(
+				c.Logging(
+					c.Recovery(
+						CORS(handler),
+					),
 				),
 			),
 		),
@@ -200,12 +380,14 @@ func (c *Client) HTTPHandler(handler http.HandlerFunc, operationName string) htt
 
 // HTTPHandlerWithTimeout creates a fully instrumented HTTP handler with timeout
 func (c *Client) HTTPHandlerWithTimeout(handler http.HandlerFunc, operationName string, timeout time.Duration) http.Handler {
-	return OTelHTTP(operationName)(
-		RequestID(
-			Logging(
-				Recovery(
-					Timeout(timeout)(
-						CORS(handler),
+	return c.OTelHTTP(operationName)(
+		c.baggage(
+			RequestID(
+				c.Logging(
+					c.Recovery(
+						Timeout(timeout)(
+							CORS(handler),
+						),
 					),
 				),
 			),
@@ -215,10 +397,12 @@ func (c *Client) HTTPHandlerWithTimeout(handler http.HandlerFunc, operationName
 
 // Handler creates a basic handler with just logging and recovery (no CORS)
 func (c *Client) Handler(handler http.HandlerFunc, operationName string) http.Handler {
-	return OTelHTTP(operationName)(
-		RequestID(
-			Logging(
-				Recovery(handler),
+	return c.OTelHTTP(operationName)(
+		c.baggage(
+			RequestID(
+				c.Logging(
+					c.Recovery(handler),
+				),
 			),
 		),
 	)
@@ -259,10 +443,11 @@ func (mc *MiddlewareChain) Append(middlewares ...func(http.Handler) http.Handler
 // StandardChain creates a standard middleware chain with common middleware
 func (c *Client) StandardChain(operationName string) *MiddlewareChain {
 	return NewChain(
-		OTelHTTP(operationName),
+		c.OTelHTTP(operationName),
+		c.baggage,
 		RequestID,
-		Logging,
-		Recovery,
+		c.Logging,
+		c.Recovery,
 		CORS,
 	)
 }
@@ -270,10 +455,11 @@ func (c *Client) StandardChain(operationName string) *MiddlewareChain {
 // APIChain creates an API-optimized middleware chain
 func (c *Client) APIChain(operationName string, timeout time.Duration) *MiddlewareChain {
 	chain := NewChain(
-		OTelHTTP(operationName),
+		c.OTelHTTP(operationName),
+		c.baggage,
 		RequestID,
-		Logging,
-		Recovery,
+		c.Logging,
+		c.Recovery,
 	)
 
 	if timeout > 0 {
@@ -1,11 +1,28 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"cloud.google.com/go/logging/apiv2/loggingpb"
 	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func TestConfig_SetDefaults(t *testing.T) {
@@ -187,6 +204,10 @@ func TestNewClient(t *testing.T) {
 			t.Error("Client logger is nil")
 		}
 
+		if client.unregisterExit == nil {
+			t.Error("NewClient() did not register a Fatal exit hook")
+		}
+
 		// Shutdown
 		ctx := context.Background()
 		if err := client.Shutdown(ctx); err != nil {
@@ -208,6 +229,357 @@ func TestNewClient(t *testing.T) {
 	})
 }
 
+func TestIsolatedClient(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	defaultLogger := slog.Default()
+	defaultProvider := otel.GetTracerProvider()
+
+	config := Config{
+		ServiceName:   "isolated-service",
+		ProjectID:     "test-project",
+		EnableConsole: true,
+		EnableTracing: true,
+		TraceRatio:    1.0,
+		TraceExporter: telemetry.ExporterStdout,
+		Isolated:      true,
+	}
+	first, err := NewClient(ctx, config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer first.Shutdown(ctx)
+
+	config.ServiceName = "second-service"
+	second, err := NewClient(ctx, config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer second.Shutdown(ctx)
+
+	if slog.Default() != defaultLogger {
+		t.Error("isolated client replaced the slog default")
+	}
+	if otel.GetTracerProvider() != defaultProvider {
+		t.Error("isolated client replaced the global tracer provider")
+	}
+	if telemetry.Global() != nil {
+		t.Error("isolated client set the global telemetry provider")
+	}
+	if first.Telemetry().TracerProvider() == second.Telemetry().TracerProvider() {
+		t.Error("isolated clients share a tracer provider")
+	}
+	if first.Logger() == second.Logger() {
+		t.Error("isolated clients share a logger")
+	}
+}
+
+func TestResetGlobals(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	defaultLogger := slog.Default()
+
+	client, err := NewClient(ctx, Config{
+		ServiceName:   "global-service",
+		ProjectID:     "test-project",
+		EnableConsole: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if slog.Default() == defaultLogger {
+		t.Error("NewClient() did not install the slog default")
+	}
+	first := logger.Global()
+	if err := client.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	ResetGlobals()
+	if slog.Default() != defaultLogger {
+		t.Error("ResetGlobals() did not restore the slog default")
+	}
+
+	// The next client installs its own global logger instead of keeping the first
+	client, err = NewClient(ctx, Config{
+		ServiceName:   "next-service",
+		ProjectID:     "test-project",
+		EnableConsole: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Shutdown(ctx)
+	if logger.Global() == first {
+		t.Error("global logger kept the first client's instance after ResetGlobals")
+	}
+}
+
+func TestClientSharesGlobalLogger(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	var buf bytes.Buffer
+	client, err := NewClient(ctx, Config{
+		ServiceName: "audit-service",
+		ProjectID:   "test-project",
+		LogHandlers: []slog.Handler{slog.NewJSONHandler(&buf, nil)},
+		Audit:       &logger.AuditConfig{HashChain: true},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Shutdown(ctx)
+
+	if logger.Global() != client.Logger() {
+		t.Fatal("global logger is not the client's logger")
+	}
+
+	// Global and client audit entries interleave into one chain
+	for i, log := range []*logger.Logger{logger.Global(), client.Logger(), logger.Global(), client.Logger()} {
+		if err := log.Audit(ctx, logger.AuditEvent{Action: fmt.Sprint("action-", i), Outcome: logger.OutcomeSuccess}); err != nil {
+			t.Fatalf("Audit() error = %v", err)
+		}
+	}
+
+	var records []logger.AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry struct {
+			Audit *logger.AuditRecord `json:"audit"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		if entry.Audit != nil {
+			records = append(records, *entry.Audit)
+		}
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d audit records, want 4", len(records))
+	}
+	if err := logger.VerifyAuditChain(records); err != nil {
+		t.Errorf("VerifyAuditChain() error = %v", err)
+	}
+}
+
+func TestClientRateLimitIsShared(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	var buf bytes.Buffer
+	client, err := NewClient(ctx, Config{
+		ServiceName: "rate-limited-service",
+		ProjectID:   "test-project",
+		LogHandlers: []slog.Handler{slog.NewJSONHandler(&buf, nil)},
+		RateLimit:   &logger.RateLimitConfig{Burst: 2, Interval: time.Hour},
+		AsyncLog:    &logger.AsyncConfig{},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// One budget and one queue cover the client and global logger
+	for range 3 {
+		client.Logger().InfoContext(ctx, "repeated")
+		logger.Global().InfoContext(ctx, "repeated")
+	}
+	if err := client.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if n := strings.Count(buf.String(), `"msg":"repeated"`); n != 2 {
+		t.Errorf("wrote %d entries, want the shared burst of 2", n)
+	}
+}
+
+// loggingServer accepts Cloud Logging writes and counts client connections
+type loggingServer struct {
+	loggingpb.UnimplementedLoggingServiceV2Server
+	conns atomic.Int32
+}
+
+func (s *loggingServer) WriteLogEntries(context.Context, *loggingpb.WriteLogEntriesRequest) (*loggingpb.WriteLogEntriesResponse, error) {
+	return &loggingpb.WriteLogEntriesResponse{}, nil
+}
+
+// countingListener counts accepted connections
+type countingListener struct {
+	net.Listener
+	conns *atomic.Int32
+}
+
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.conns.Add(1)
+	}
+	return conn, err
+}
+
+func TestClientOpensOneCloudLoggingClient(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	fake := &loggingServer{}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv := grpc.NewServer()
+	loggingpb.RegisterLoggingServiceV2Server(srv, fake)
+	go srv.Serve(countingListener{Listener: lis, conns: &fake.conns})
+	t.Cleanup(srv.Stop)
+
+	client, err := NewClient(ctx, Config{
+		ServiceName: "cloud-logging-service",
+		ProjectID:   "test-project",
+		CloudLogging: &logger.CloudLoggingConfig{
+			ClientOptions: []option.ClientOption{
+				option.WithEndpoint(lis.Addr().String()),
+				option.WithoutAuthentication(),
+				option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	logger.Global().InfoContext(ctx, "from the global logger")
+	client.Logger().InfoContext(ctx, "from the client logger")
+	if err := client.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if n := fake.conns.Load(); n != 1 {
+		t.Errorf("Cloud Logging connections = %d, want 1 shared by the client and global logger", n)
+	}
+}
+
+func TestNewClientTelemetryFailure(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	failing := Config{
+		ServiceName:   "failing-service",
+		ProjectID:     "test-project",
+		EnableTracing: true,
+		TraceExporter: "bogus",
+	}
+
+	t.Run("global logger untouched", func(t *testing.T) {
+		var buf bytes.Buffer
+		config := failing
+		config.LogHandlers = []slog.Handler{slog.NewJSONHandler(&buf, nil)}
+		if _, err := NewClient(ctx, config); err == nil {
+			t.Fatal("NewClient() expected telemetry error")
+		}
+
+		logger.Global().Info("after failure")
+		if strings.Contains(buf.String(), "after failure") {
+			t.Error("failed client's logger was left installed as the global logger")
+		}
+	})
+
+	t.Run("logger shut down", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		config := failing
+		config.AsyncLog = &logger.AsyncConfig{}
+		if _, err := NewClient(ctx, config); err == nil {
+			t.Fatal("NewClient() expected telemetry error")
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if n := runtime.NumGoroutine(); n > before {
+			t.Errorf("goroutines = %d after failed NewClient, want at most %d", n, before)
+		}
+	})
+}
+
+func TestClientBoundMiddleware(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	newClient := func(name string, buf *bytes.Buffer) *Client {
+		t.Helper()
+		client, err := NewClient(ctx, Config{
+			ServiceName:   name,
+			ProjectID:     "test-project",
+			LogHandlers:   []slog.Handler{slog.NewJSONHandler(buf, nil)},
+			EnableTracing: true,
+			TraceRatio:    1.0,
+			TraceExporter: telemetry.ExporterStdout,
+			Isolated:      true,
+		})
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		t.Cleanup(func() { client.Shutdown(ctx) })
+		return client
+	}
+
+	var bufA, bufB bytes.Buffer
+	clientA := newClient("service-a", &bufA)
+	newClient("service-b", &bufB)
+	bufA.Reset()
+	bufB.Reset()
+
+	t.Run("request log goes to the client's logger", func(t *testing.T) {
+		handler := clientA.HTTPHandler(func(w http.ResponseWriter, r *http.Request) {
+			if !trace.SpanFromContext(r.Context()).IsRecording() {
+				t.Error("handler span is not recorded by the client's provider")
+			}
+		}, "GET /orders")
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+		if !strings.Contains(bufA.String(), "HTTP request completed") {
+			t.Errorf("client A log = %q, want request log", bufA.String())
+		}
+		if !strings.Contains(bufA.String(), `"trace_id"`) {
+			t.Errorf("client A log = %q, want trace correlation", bufA.String())
+		}
+		if bufB.Len() != 0 {
+			t.Errorf("client B log = %q, want nothing", bufB.String())
+		}
+	})
+
+	t.Run("recovered panic goes to the client's logger", func(t *testing.T) {
+		bufA.Reset()
+		handler := clientA.Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d, want 500", rec.Code)
+		}
+		if !strings.Contains(bufA.String(), "Panic recovered") || !strings.Contains(bufA.String(), `"panic.value":"boom"`) {
+			t.Errorf("client A log = %q, want panic log", bufA.String())
+		}
+	})
+
+	t.Run("custom span uses the client's tracer", func(t *testing.T) {
+		handler := clientA.CustomSpan("custom")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !trace.SpanFromContext(r.Context()).IsRecording() {
+				t.Error("custom span is not recorded by the client's provider")
+			}
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
 func TestMiddlewareChain(t *testing.T) {
 	t.Run("create empty chain", func(t *testing.T) {
 		chain := NewChain()
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers Cloud Tasks sets on HTTP target requests
+const (
+	headerTaskQueueName        = "X-CloudTasks-QueueName"
+	headerTaskName             = "X-CloudTasks-TaskName"
+	headerTaskRetryCount       = "X-CloudTasks-TaskRetryCount"
+	headerTaskExecutionCount   = "X-CloudTasks-TaskExecutionCount"
+	headerTaskETA              = "X-CloudTasks-TaskETA"
+	headerTaskPreviousResponse = "X-CloudTasks-TaskPreviousResponse"
+	headerTaskRetryReason      = "X-CloudTasks-TaskRetryReason"
+)
+
+// CloudTask describes the Cloud Tasks task a request executes
+type CloudTask struct {
+	QueueName        string
+	TaskName         string
+	RetryCount       int       // Previous attempts of this task
+	ExecutionCount   int       // Previous attempts that received a response
+	ETA              time.Time // When the task was scheduled to run
+	PreviousResponse int       // HTTP status of the previous attempt, if any
+	RetryReason      string    // Why the task is being retried, if it is
+	FinalAttempt     bool      // No retry follows a failure of this attempt
+}
+
+// Attempt returns the 1-based number of this attempt
+func (t CloudTask) Attempt() int {
+	return t.RetryCount + 1
+}
+
+type cloudTaskKey struct{}
+
+// CloudTaskFromContext returns the task stored by the CloudTasks middleware
+func CloudTaskFromContext(ctx context.Context) (CloudTask, bool) {
+	t, ok := ctx.Value(cloudTaskKey{}).(CloudTask)
+	return t, ok
+}
+
+// cloudTasksOptions configure the CloudTasks middleware
+type cloudTasksOptions struct {
+	logger *logger.Logger
+}
+
+// CloudTasksOption configures the CloudTasks middleware
+type CloudTasksOption func(*cloudTasksOptions)
+
+// WithTaskLogger makes WriteTaskResult log to l instead of the global logger
+func WithTaskLogger(l *logger.Logger) CloudTasksOption {
+	return func(o *cloudTasksOptions) {
+		o.logger = l
+	}
+}
+
+type taskLoggerKey struct{}
+
+// taskLogger returns the logger the CloudTasks middleware stored, or the global one
+func taskLogger(ctx context.Context) *logger.Logger {
+	if l, ok := ctx.Value(taskLoggerKey{}).(*logger.Logger); ok {
+		return l
+	}
+	return logger.Global()
+}
+
+// CloudTasks middleware parses the X-CloudTasks-* headers of Cloud Tasks HTTP
+// target requests into a CloudTask in the context, and adds the task to the
+// active span and to logs made with the request context. maxAttempts is the
+// queue's retry config max attempts and flags the last one as FinalAttempt;
+// zero or negative means unlimited. Requests without the headers pass through.
+//
+// Anyone who can reach the service can set these headers, so use it behind
+// auth.GoogleOIDC when they drive decisions.
+func CloudTasks(maxAttempts int, opts ...CloudTasksOption) func(http.Handler) http.Handler {
+	var o cloudTasksOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if o.logger != nil {
+				ctx = context.WithValue(ctx, taskLoggerKey{}, o.logger)
+			}
+
+			task, ok := parseCloudTask(r.Header, maxAttempts)
+			if !ok {
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			attrs := []attribute.KeyValue{
+				attribute.String("cloudtasks.queue_name", task.QueueName),
+				attribute.String("cloudtasks.task_name", task.TaskName),
+				attribute.Int("cloudtasks.retry_count", task.RetryCount),
+				attribute.Int("cloudtasks.execution_count", task.ExecutionCount),
+				attribute.Bool("cloudtasks.final_attempt", task.FinalAttempt),
+			}
+			args := []any{
+				"cloudtasks.queue_name", task.QueueName,
+				"cloudtasks.task_name", task.TaskName,
+				"cloudtasks.retry_count", task.RetryCount,
+				"cloudtasks.execution_count", task.ExecutionCount,
+				"cloudtasks.final_attempt", task.FinalAttempt,
+			}
+			if !task.ETA.IsZero() {
+				eta := task.ETA.Format(time.RFC3339Nano)
+				attrs = append(attrs, attribute.String("cloudtasks.eta", eta))
+				args = append(args, "cloudtasks.eta", eta)
+			}
+			trace.SpanFromContext(ctx).SetAttributes(attrs...)
+			ctx = logger.WithAttrs(ctx, args...)
+			ctx = context.WithValue(ctx, cloudTaskKey{}, task)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseCloudTask reads the task headers; unparsable counts are left at zero
+func parseCloudTask(h http.Header, maxAttempts int) (CloudTask, bool) {
+	queue := h.Get(headerTaskQueueName)
+	if queue == "" {
+		return CloudTask{}, false
+	}
+	task := CloudTask{
+		QueueName:   queue,
+		TaskName:    h.Get(headerTaskName),
+		RetryReason: h.Get(headerTaskRetryReason),
+	}
+	task.RetryCount, _ = strconv.Atoi(h.Get(headerTaskRetryCount))
+	task.ExecutionCount, _ = strconv.Atoi(h.Get(headerTaskExecutionCount))
+	task.PreviousResponse, _ = strconv.Atoi(h.Get(headerTaskPreviousResponse))
+
+	// The ETA is in seconds since the epoch, possibly fractional
+	if eta, err := strconv.ParseFloat(h.Get(headerTaskETA), 64); err == nil && eta > 0 {
+		sec, frac := math.Modf(eta)
+		task.ETA = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	}
+
+	task.FinalAttempt = maxAttempts > 0 && task.Attempt() >= maxAttempts
+	return task, true
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// PermanentTaskError marks err as permanent, so WriteTaskResult acknowledges
+// the task instead of having Cloud Tasks retry it
+func PermanentTaskError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanentTaskError reports whether err was marked by PermanentTaskError
+func IsPermanentTaskError(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// WriteTaskResult responds to a Cloud Tasks request with the status matching
+// the handler's result. Cloud Tasks retries any status outside 2xx, so success
+// and permanent errors get 200 while other errors get 500. Errors are recorded
+// on the span and logged to the CloudTasks middleware's logger; permanent
+// errors are also flagged with cloudtasks.permanent_error.
+func WriteTaskResult(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+	task, _ := CloudTaskFromContext(ctx)
+
+	if IsPermanentTaskError(err) {
+		telemetry.RecordError(span, err, "task failed permanently")
+		span.SetAttributes(attribute.Bool("cloudtasks.permanent_error", true))
+		taskLogger(ctx).ErrorContext(ctx, "Cloud Task failed permanently, not retrying",
+			"error", err,
+			"cloudtasks.permanent_error", true,
+		)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	telemetry.RecordError(span, err, "task failed")
+	msg := "Cloud Task failed, will be retried"
+	if task.FinalAttempt {
+		msg = "Cloud Task failed on its final attempt"
+	}
+	taskLogger(ctx).ErrorContext(ctx, msg, "error", err)
+	http.Error(w, "task failed", http.StatusInternalServerError)
+}
@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// taskRequest returns a request carrying Cloud Tasks headers
+func taskRequest(retryCount int) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/tasks/send-email", nil)
+	req.Header.Set("X-CloudTasks-QueueName", "emails")
+	req.Header.Set("X-CloudTasks-TaskName", "task-123")
+	req.Header.Set("X-CloudTasks-TaskRetryCount", fmt.Sprint(retryCount))
+	req.Header.Set("X-CloudTasks-TaskExecutionCount", fmt.Sprint(retryCount))
+	req.Header.Set("X-CloudTasks-TaskETA", "1767268800.25")
+	if retryCount > 0 {
+		req.Header.Set("X-CloudTasks-TaskPreviousResponse", "500")
+		req.Header.Set("X-CloudTasks-TaskRetryReason", "HTTP status code 500")
+	}
+	return req
+}
+
+// tracedRequest runs handler inside a recorded span
+func tracedRequest(t *testing.T, handler http.Handler, req *http.Request) (*httptest.ResponseRecorder, sdktrace.ReadOnlySpan) {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	defer tp.Shutdown(context.Background())
+
+	ctx, span := tp.Tracer("test").Start(req.Context(), "request")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+	span.End()
+	return w, rec.Ended()[0]
+}
+
+func TestCloudTasks(t *testing.T) {
+	var got CloudTask
+	var gotCtx context.Context
+	handler := CloudTasks(5)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = CloudTaskFromContext(r.Context())
+		gotCtx = r.Context()
+	}))
+
+	t.Run("parses headers", func(t *testing.T) {
+		_, span := tracedRequest(t, handler, taskRequest(2))
+
+		want := CloudTask{
+			QueueName:        "emails",
+			TaskName:         "task-123",
+			RetryCount:       2,
+			ExecutionCount:   2,
+			ETA:              time.Date(2026, 1, 1, 12, 0, 0, 25e7, time.UTC),
+			PreviousResponse: 500,
+			RetryReason:      "HTTP status code 500",
+		}
+		if got != want {
+			t.Errorf("task = %+v, want %+v", got, want)
+		}
+		if got.Attempt() != 3 {
+			t.Errorf("Attempt() = %d, want 3", got.Attempt())
+		}
+
+		attrs := map[string]string{}
+		for _, kv := range span.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		if attrs["cloudtasks.queue_name"] != "emails" || attrs["cloudtasks.retry_count"] != "2" ||
+			attrs["cloudtasks.execution_count"] != "2" || attrs["cloudtasks.final_attempt"] != "false" ||
+			attrs["cloudtasks.eta"] != "2026-01-01T12:00:00.25Z" {
+			t.Errorf("span attributes = %v", attrs)
+		}
+
+		logAttrs := map[string]string{}
+		for _, a := range logger.AttrsFromContext(gotCtx) {
+			logAttrs[a.Key] = a.Value.String()
+		}
+		if logAttrs["cloudtasks.task_name"] != "task-123" || logAttrs["cloudtasks.execution_count"] != "2" ||
+			logAttrs["cloudtasks.eta"] != "2026-01-01T12:00:00.25Z" {
+			t.Errorf("log attrs = %v", logAttrs)
+		}
+	})
+
+	t.Run("final attempt", func(t *testing.T) {
+		tracedRequest(t, handler, taskRequest(4))
+		if !got.FinalAttempt {
+			t.Error("FinalAttempt = false on attempt 5 of 5")
+		}
+	})
+
+	t.Run("unlimited attempts", func(t *testing.T) {
+		unlimited := CloudTasks(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = CloudTaskFromContext(r.Context())
+		}))
+		tracedRequest(t, unlimited, taskRequest(100))
+		if got.FinalAttempt {
+			t.Error("FinalAttempt = true without a max attempts")
+		}
+	})
+
+	t.Run("other requests pass through", func(t *testing.T) {
+		var ok bool
+		h := CloudTasks(5)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, ok = CloudTaskFromContext(r.Context())
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+		if ok {
+			t.Error("CloudTaskFromContext() ok = true without Cloud Tasks headers")
+		}
+	})
+}
+
+func TestPermanentTaskError(t *testing.T) {
+	cause := errors.New("invalid address")
+	err := fmt.Errorf("send email: %w", PermanentTaskError(cause))
+
+	if !IsPermanentTaskError(err) {
+		t.Error("IsPermanentTaskError() = false for a wrapped permanent error")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is() = false for the cause")
+	}
+	if IsPermanentTaskError(cause) {
+		t.Error("IsPermanentTaskError() = true for a plain error")
+	}
+	if PermanentTaskError(nil) != nil {
+		t.Error("PermanentTaskError(nil) != nil")
+	}
+}
+
+func TestWriteTaskResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   codes.Code
+	}{
+		{"success", nil, http.StatusOK, codes.Unset},
+		{"retryable", errors.New("smtp timeout"), http.StatusInternalServerError, codes.Error},
+		{"permanent", PermanentTaskError(errors.New("invalid address")), http.StatusOK, codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log, err := logger.NewLogger(context.Background(), logger.Config{
+				ProjectID: "test-project",
+				Handlers:  []slog.Handler{slog.NewJSONHandler(&buf, nil)},
+			})
+			if err != nil {
+				t.Fatalf("NewLogger() error = %v", err)
+			}
+			handler := CloudTasks(5, WithTaskLogger(log))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				WriteTaskResult(w, r, tt.err)
+			}))
+			w, span := tracedRequest(t, handler, taskRequest(0))
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if span.Status().Code != tt.code {
+				t.Errorf("span status = %v, want %v", span.Status().Code, tt.code)
+			}
+			if logged := strings.Contains(buf.String(), `"level":"ERROR"`); logged != (tt.err != nil) {
+				t.Errorf("error logged to the task logger = %v, want %v: %s", logged, tt.err != nil, buf.String())
+			}
+		})
+	}
+}
+
+func TestClientCloudTasks(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	client, err := NewClient(ctx, Config{
+		ServiceName: "tasks-service",
+		ProjectID:   "test-project",
+		LogHandlers: []slog.Handler{slog.NewJSONHandler(&buf, nil)},
+		Isolated:    true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Shutdown(ctx)
+
+	handler := client.CloudTasks(5)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteTaskResult(w, r, errors.New("smtp timeout"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), taskRequest(0))
+
+	if !strings.Contains(buf.String(), "smtp timeout") {
+		t.Errorf("client log = %s, want the task failure", buf.String())
+	}
+}
@@ -0,0 +1,345 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the on-disk form of Config read by LoadConfig
+type fileConfig struct {
+	ServiceName          string            `json:"service_name" yaml:"service_name"`
+	ServiceVersion       string            `json:"service_version" yaml:"service_version"`
+	Environment          string            `json:"environment" yaml:"environment"`
+	ProjectID            string            `json:"project_id" yaml:"project_id"`
+	LogName              string            `json:"log_name" yaml:"log_name"`
+	EnableConsole        *bool             `json:"enable_console" yaml:"enable_console"`
+	EnableGCP            *bool             `json:"enable_gcp" yaml:"enable_gcp"`
+	LogLevel             string            `json:"log_level" yaml:"log_level"`
+	PrettyLog            *bool             `json:"pretty_log" yaml:"pretty_log"`
+	EnableErrorReporting *bool             `json:"enable_error_reporting" yaml:"enable_error_reporting"`
+	EnableTracing        *bool             `json:"enable_tracing" yaml:"enable_tracing"`
+	EnableMetrics        *bool             `json:"enable_metrics" yaml:"enable_metrics"`
+	TraceRatio           *float64          `json:"trace_ratio" yaml:"trace_ratio"`
+	Attributes           map[string]string `json:"attributes" yaml:"attributes"`
+}
+
+// configLoader builds a Config from layered sources and remembers where each
+// field came from, so errors can point at the file key or variable to fix
+type configLoader struct {
+	config  Config
+	sources map[string]string
+}
+
+func newConfigLoader() *configLoader {
+	return &configLoader{sources: make(map[string]string)}
+}
+
+// set records the source of a field
+func (l *configLoader) set(field, source string) {
+	l.sources[field] = source
+}
+
+// source describes where a field came from
+func (l *configLoader) source(field string) string {
+	if s, ok := l.sources[field]; ok {
+		return s
+	}
+	return "default"
+}
+
+// ConfigFromEnv builds a validated Config from environment variables.
+//
+// Recognized variables:
+//   - OTEL_SERVICE_NAME: ServiceName
+//   - K_SERVICE and K_REVISION (set by Cloud Run): ServiceName and
+//     ServiceVersion, when nothing else sets them
+//   - GOOGLE_CLOUD_PROJECT, or GCP_PROJECT: ProjectID
+//   - ENVIRONMENT: Environment
+//   - LOG_LEVEL: LogLevel, any name accepted by logger.ParseLevel
+//   - OTEL_TRACES_SAMPLER, OTEL_TRACES_SAMPLER_ARG: EnableTracing and TraceRatio;
+//     a ratio of 0 sets a Sampler that records no new traces
+//   - OTEL_RESOURCE_ATTRIBUTES: Attributes; service.name and service.version
+//     set ServiceName and ServiceVersion, below OTEL_SERVICE_NAME
+//
+// When no file enables a sink, GCP logging is enabled on Cloud Run and
+// console logging everywhere else.
+func ConfigFromEnv() (Config, error) {
+	l := newConfigLoader()
+	if err := l.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	return l.finish()
+}
+
+// LoadConfig reads a JSON or YAML config file, chosen by extension, and
+// applies environment variables on top as ConfigFromEnv does. Unknown keys
+// are rejected.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var fc fileConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&fc)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&fc)
+		if errors.Is(err, io.EOF) {
+			err = nil // empty file
+		}
+	default:
+		return Config{}, fmt.Errorf("%s: unsupported config format %q (want .json, .yaml or .yml)", path, ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	l := newConfigLoader()
+	if err := l.applyFile(path, fc); err != nil {
+		return Config{}, err
+	}
+	if err := l.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	return l.finish()
+}
+
+// applyFile copies the fields present in the file
+func (l *configLoader) applyFile(path string, fc fileConfig) error {
+	src := func(key string) string { return fmt.Sprintf("%s: %s", path, key) }
+	c := &l.config
+
+	setString := func(dst *string, v, field, key string) {
+		if v != "" {
+			*dst = v
+			l.set(field, src(key))
+		}
+	}
+	setBool := func(dst *bool, v *bool, field, key string) {
+		if v != nil {
+			*dst = *v
+			l.set(field, src(key))
+		}
+	}
+
+	setString(&c.ServiceName, fc.ServiceName, "ServiceName", "service_name")
+	setString(&c.ServiceVersion, fc.ServiceVersion, "ServiceVersion", "service_version")
+	setString(&c.Environment, fc.Environment, "Environment", "environment")
+	setString(&c.ProjectID, fc.ProjectID, "ProjectID", "project_id")
+	setString(&c.LogName, fc.LogName, "LogName", "log_name")
+	setBool(&c.EnableConsole, fc.EnableConsole, "EnableConsole", "enable_console")
+	setBool(&c.EnableGCP, fc.EnableGCP, "EnableGCP", "enable_gcp")
+	setBool(&c.PrettyLog, fc.PrettyLog, "PrettyLog", "pretty_log")
+	setBool(&c.EnableErrorReporting, fc.EnableErrorReporting, "EnableErrorReporting", "enable_error_reporting")
+	setBool(&c.EnableTracing, fc.EnableTracing, "EnableTracing", "enable_tracing")
+	setBool(&c.EnableMetrics, fc.EnableMetrics, "EnableMetrics", "enable_metrics")
+
+	if fc.LogLevel != "" {
+		level, err := logger.ParseLevel(fc.LogLevel)
+		if err != nil {
+			return fmt.Errorf("%s: %w", src("log_level"), err)
+		}
+		c.LogLevel = level
+		l.set("LogLevel", src("log_level"))
+	}
+	if fc.TraceRatio != nil {
+		c.TraceRatio = *fc.TraceRatio
+		l.set("TraceRatio", src("trace_ratio"))
+	}
+	if len(fc.Attributes) > 0 {
+		c.Attributes = fc.Attributes
+		l.set("Attributes", src("attributes"))
+	}
+	return nil
+}
+
+// applyEnv copies the recognized environment variables that are set
+func (l *configLoader) applyEnv() error {
+	c := &l.config
+
+	setString := func(dst *string, field string, keys ...string) {
+		for _, key := range keys {
+			if v := os.Getenv(key); v != "" {
+				*dst = v
+				l.set(field, "env "+key)
+				return
+			}
+		}
+	}
+	// Platform-injected variables only fill fields nothing else set
+	setFallback := func(dst *string, field, key string) {
+		if _, ok := l.sources[field]; !ok {
+			setString(dst, field, key)
+		}
+	}
+
+	if err := l.applyResourceAttributes(); err != nil {
+		return err
+	}
+	setString(&c.ServiceName, "ServiceName", "OTEL_SERVICE_NAME")
+	setFallback(&c.ServiceName, "ServiceName", "K_SERVICE")
+	setFallback(&c.ServiceVersion, "ServiceVersion", "K_REVISION")
+	setString(&c.ProjectID, "ProjectID", "GOOGLE_CLOUD_PROJECT", "GCP_PROJECT")
+	setString(&c.Environment, "Environment", "ENVIRONMENT")
+
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		level, err := logger.ParseLevel(v)
+		if err != nil {
+			return fmt.Errorf("env LOG_LEVEL=%q: %w", v, err)
+		}
+		c.LogLevel = level
+		l.set("LogLevel", "env LOG_LEVEL")
+	}
+
+	if err := l.applySampler(); err != nil {
+		return err
+	}
+
+	// Default to the sink that suits the platform unless a file chose
+	_, consoleSet := l.sources["EnableConsole"]
+	_, gcpSet := l.sources["EnableGCP"]
+	if !consoleSet && !gcpSet {
+		if os.Getenv("K_SERVICE") != "" {
+			c.EnableGCP = true
+			l.set("EnableGCP", "env K_SERVICE (running on Cloud Run)")
+		} else {
+			c.EnableConsole = true
+		}
+	}
+	return nil
+}
+
+// applyResourceAttributes parses OTEL_RESOURCE_ATTRIBUTES ("k1=v1,k2=v2", values percent-encoded)
+func (l *configLoader) applyResourceAttributes() error {
+	raw := os.Getenv("OTEL_RESOURCE_ATTRIBUTES")
+	if raw == "" {
+		return nil
+	}
+	const source = "env OTEL_RESOURCE_ATTRIBUTES"
+
+	c := &l.config
+	if c.Attributes == nil {
+		c.Attributes = make(map[string]string)
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("%s: invalid entry %q (want key=value)", source, pair)
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s: invalid value for %q: %w", source, key, err)
+		}
+
+		switch key {
+		case "service.name":
+			c.ServiceName = value
+			l.set("ServiceName", source)
+		case "service.version":
+			c.ServiceVersion = value
+			l.set("ServiceVersion", source)
+		default:
+			c.Attributes[key] = value
+			l.set("Attributes", source)
+		}
+	}
+	return nil
+}
+
+// applySampler maps the OpenTelemetry sampler variables onto EnableTracing and TraceRatio
+func (l *configLoader) applySampler() error {
+	c := &l.config
+	sampler := strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_TRACES_SAMPLER")))
+	arg := strings.TrimSpace(os.Getenv("OTEL_TRACES_SAMPLER_ARG"))
+	source := fmt.Sprintf("env OTEL_TRACES_SAMPLER=%q", sampler)
+
+	if strings.HasPrefix(sampler, "parentbased_") {
+		c.ParentBasedSampling = true
+		l.set("ParentBasedSampling", source)
+	}
+
+	switch sampler {
+	case "":
+		if arg == "" {
+			return nil
+		}
+		sampler = "traceidratio"
+	case "always_on", "parentbased_always_on":
+		c.EnableTracing = true
+		c.TraceRatio = 1
+		l.set("EnableTracing", source)
+		l.set("TraceRatio", source)
+		return nil
+	case "always_off", "parentbased_always_off":
+		c.EnableTracing = false
+		l.set("EnableTracing", source)
+		return nil
+	case "traceidratio", "parentbased_traceidratio":
+	default:
+		return fmt.Errorf("%s: unsupported sampler (want always_on, always_off, traceidratio or their parentbased_ forms)", source)
+	}
+
+	// Ratio samplers default to 1.0 without an argument, as in the OpenTelemetry spec
+	ratio := 1.0
+	if arg != "" {
+		var err error
+		ratio, err = strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("env OTEL_TRACES_SAMPLER_ARG=%q: not a number", arg)
+		}
+		source = fmt.Sprintf("env OTEL_TRACES_SAMPLER_ARG=%q", arg)
+	}
+	c.EnableTracing = true
+	c.TraceRatio = ratio
+	l.set("EnableTracing", source)
+	l.set("TraceRatio", source)
+	return nil
+}
+
+// finish applies defaults and validates, naming the source of a bad value
+func (l *configLoader) finish() (Config, error) {
+	c := l.config
+
+	if c.TraceRatio < 0 || c.TraceRatio > 1 {
+		return Config{}, fmt.Errorf("TraceRatio %v from %s must be between 0.0 and 1.0", c.TraceRatio, l.source("TraceRatio"))
+	}
+	if c.ProjectID == "" {
+		return Config{}, fmt.Errorf("ProjectID is required: set GOOGLE_CLOUD_PROJECT or project_id in the config file")
+	}
+
+	// An explicit ratio of 0 samples nothing; SetDefaults would read it as unset
+	if _, ok := l.sources["TraceRatio"]; ok && c.TraceRatio == 0 && c.Sampler == nil {
+		c.Sampler = sdktrace.NeverSample()
+		if c.ParentBasedSampling {
+			c.Sampler = sdktrace.ParentBased(c.Sampler)
+		}
+	}
+
+	c.SetDefaults()
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
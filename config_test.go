@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+)
+
+// configEnvVars are the variables read by ConfigFromEnv and LoadConfig
+var configEnvVars = []string{
+	"OTEL_SERVICE_NAME", "OTEL_TRACES_SAMPLER", "OTEL_TRACES_SAMPLER_ARG", "OTEL_RESOURCE_ATTRIBUTES",
+	"LOG_LEVEL", "K_SERVICE", "K_REVISION", "GOOGLE_CLOUD_PROJECT", "GCP_PROJECT", "ENVIRONMENT",
+}
+
+// clearConfigEnv unsets the config variables for the duration of the test
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range configEnvVars {
+		t.Setenv(key, "")
+	}
+}
+
+// writeConfigFile writes content to a temp file with the given name
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("standard variables", func(t *testing.T) {
+		clearConfigEnv(t)
+		t.Setenv("OTEL_SERVICE_NAME", "orders")
+		t.Setenv("GOOGLE_CLOUD_PROJECT", "proj")
+		t.Setenv("LOG_LEVEL", "notice")
+		t.Setenv("OTEL_TRACES_SAMPLER", "parentbased_traceidratio")
+		t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0.25")
+		t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "team=payments,service.version=1.2.3,note=a%20b")
+
+		c, err := ConfigFromEnv()
+		if err != nil {
+			t.Fatalf("ConfigFromEnv() error = %v", err)
+		}
+		if c.ServiceName != "orders" || c.ProjectID != "proj" || c.ServiceVersion != "1.2.3" {
+			t.Errorf("service = %q %q %q", c.ServiceName, c.ProjectID, c.ServiceVersion)
+		}
+		if c.LogLevel != logger.LevelNotice {
+			t.Errorf("LogLevel = %v, want NOTICE", c.LogLevel)
+		}
+		if !c.EnableTracing || c.TraceRatio != 0.25 || !c.ParentBasedSampling {
+			t.Errorf("tracing = %v %v %v, want true 0.25 parent-based", c.EnableTracing, c.TraceRatio, c.ParentBasedSampling)
+		}
+		if c.Attributes["team"] != "payments" || c.Attributes["note"] != "a b" {
+			t.Errorf("Attributes = %v", c.Attributes)
+		}
+		if _, ok := c.Attributes["service.version"]; ok {
+			t.Error("service.version kept as a custom attribute")
+		}
+		if !c.EnableConsole || c.EnableGCP {
+			t.Errorf("sinks = console %v, gcp %v, want console outside Cloud Run", c.EnableConsole, c.EnableGCP)
+		}
+	})
+
+	t.Run("Cloud Run variables", func(t *testing.T) {
+		clearConfigEnv(t)
+		t.Setenv("K_SERVICE", "orders-svc")
+		t.Setenv("K_REVISION", "orders-svc-00012-abc")
+		t.Setenv("GCP_PROJECT", "proj")
+
+		c, err := ConfigFromEnv()
+		if err != nil {
+			t.Fatalf("ConfigFromEnv() error = %v", err)
+		}
+		if c.ServiceName != "orders-svc" || c.ServiceVersion != "orders-svc-00012-abc" {
+			t.Errorf("service = %q %q", c.ServiceName, c.ServiceVersion)
+		}
+		if !c.EnableGCP || c.EnableConsole {
+			t.Errorf("sinks = console %v, gcp %v, want GCP on Cloud Run", c.EnableConsole, c.EnableGCP)
+		}
+	})
+
+	t.Run("OTEL_SERVICE_NAME wins over K_SERVICE", func(t *testing.T) {
+		clearConfigEnv(t)
+		t.Setenv("K_SERVICE", "platform-name")
+		t.Setenv("OTEL_SERVICE_NAME", "chosen-name")
+		t.Setenv("GOOGLE_CLOUD_PROJECT", "proj")
+
+		c, err := ConfigFromEnv()
+		if err != nil {
+			t.Fatalf("ConfigFromEnv() error = %v", err)
+		}
+		if c.ServiceName != "chosen-name" {
+			t.Errorf("ServiceName = %q", c.ServiceName)
+		}
+	})
+
+	t.Run("samplers", func(t *testing.T) {
+		tests := []struct {
+			sampler, arg string
+			tracing      bool
+			ratio        float64
+		}{
+			{"always_on", "", true, 1},
+			{"always_off", "", false, 0.1},
+			{"traceidratio", "", true, 1},
+			{"", "0.5", true, 0.5},
+		}
+		for _, tt := range tests {
+			clearConfigEnv(t)
+			t.Setenv("GOOGLE_CLOUD_PROJECT", "proj")
+			t.Setenv("OTEL_TRACES_SAMPLER", tt.sampler)
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.arg)
+
+			c, err := ConfigFromEnv()
+			if err != nil {
+				t.Fatalf("sampler %q: error = %v", tt.sampler, err)
+			}
+			if c.EnableTracing != tt.tracing || c.TraceRatio != tt.ratio {
+				t.Errorf("sampler %q arg %q = %v %v, want %v %v", tt.sampler, tt.arg, c.EnableTracing, c.TraceRatio, tt.tracing, tt.ratio)
+			}
+		}
+	})
+
+	t.Run("ratio 0 samples nothing", func(t *testing.T) {
+		tests := []struct {
+			sampler, want string
+		}{
+			{"traceidratio", "AlwaysOffSampler"},
+			{"parentbased_traceidratio", "ParentBased{root:AlwaysOffSampler"},
+		}
+		for _, tt := range tests {
+			clearConfigEnv(t)
+			t.Setenv("GOOGLE_CLOUD_PROJECT", "proj")
+			t.Setenv("OTEL_TRACES_SAMPLER", tt.sampler)
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0")
+
+			c, err := ConfigFromEnv()
+			if err != nil {
+				t.Fatalf("sampler %q: error = %v", tt.sampler, err)
+			}
+			if c.Sampler == nil || !strings.HasPrefix(c.Sampler.Description(), tt.want) {
+				t.Errorf("sampler %q arg 0: Sampler = %v, want %s", tt.sampler, c.Sampler, tt.want)
+			}
+		}
+	})
+
+	t.Run("errors name their source", func(t *testing.T) {
+		tests := []struct {
+			env  map[string]string
+			want string
+		}{
+			{map[string]string{"LOG_LEVEL": "verbose"}, `env LOG_LEVEL="verbose"`},
+			{map[string]string{"OTEL_TRACES_SAMPLER": "jaeger_remote"}, `env OTEL_TRACES_SAMPLER="jaeger_remote"`},
+			{map[string]string{"OTEL_TRACES_SAMPLER_ARG": "half"}, `env OTEL_TRACES_SAMPLER_ARG="half"`},
+			{map[string]string{"OTEL_TRACES_SAMPLER_ARG": "1.5"}, `TraceRatio 1.5 from env OTEL_TRACES_SAMPLER_ARG="1.5"`},
+			{map[string]string{"OTEL_RESOURCE_ATTRIBUTES": "broken"}, "env OTEL_RESOURCE_ATTRIBUTES"},
+			{map[string]string{"GOOGLE_CLOUD_PROJECT": ""}, "GOOGLE_CLOUD_PROJECT"},
+		}
+		for _, tt := range tests {
+			clearConfigEnv(t)
+			t.Setenv("GOOGLE_CLOUD_PROJECT", "proj")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := ConfigFromEnv()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("env %v: error = %v, want it to mention %q", tt.env, err, tt.want)
+			}
+		}
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("yaml with env override", func(t *testing.T) {
+		clearConfigEnv(t)
+		t.Setenv("LOG_LEVEL", "debug")
+		t.Setenv("K_SERVICE", "ignored-platform-name")
+		path := writeConfigFile(t, "config.yaml", `
+service_name: orders
+project_id: proj
+enable_gcp: true
+log_level: warning
+trace_ratio: 0.2
+enable_tracing: true
+attributes:
+  team: payments
+`)
+		c, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if c.ServiceName != "orders" || c.ProjectID != "proj" {
+			t.Errorf("service = %q %q", c.ServiceName, c.ProjectID)
+		}
+		if c.LogLevel != logger.LevelDebug {
+			t.Errorf("LogLevel = %v, want env override DEBUG", c.LogLevel)
+		}
+		if !c.EnableGCP || c.EnableConsole {
+			t.Errorf("sinks = console %v, gcp %v, want file choice", c.EnableConsole, c.EnableGCP)
+		}
+		if !c.EnableTracing || c.TraceRatio != 0.2 || c.Attributes["team"] != "payments" {
+			t.Errorf("config = %+v", c)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		clearConfigEnv(t)
+		path := writeConfigFile(t, "config.json", `{"service_name": "orders", "project_id": "proj", "enable_console": true}`)
+		c, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if c.ServiceName != "orders" || !c.EnableConsole {
+			t.Errorf("config = %+v", c)
+		}
+	})
+
+	t.Run("trace_ratio 0 samples nothing", func(t *testing.T) {
+		clearConfigEnv(t)
+		path := writeConfigFile(t, "config.yaml", "project_id: proj\nenable_tracing: true\ntrace_ratio: 0\n")
+		c, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if c.Sampler == nil || c.Sampler.Description() != "AlwaysOffSampler" {
+			t.Errorf("Sampler = %v, want AlwaysOffSampler", c.Sampler)
+		}
+	})
+
+	t.Run("errors name their source", func(t *testing.T) {
+		tests := []struct {
+			name, content, want string
+		}{
+			{"config.yaml", "project_id: proj\nlog_level: loud\n", "config.yaml: log_level"},
+			{"config.yaml", "project_id: proj\ntrace_ratio: 2\n", "TraceRatio 2 from"},
+			{"config.yaml", "project_id: proj\nunknown_key: 1\n", "unknown_key"},
+			{"config.json", `{"project_id": "proj", "tracing": true}`, `unknown field "tracing"`},
+			{"config.toml", "", "unsupported config format"},
+		}
+		for _, tt := range tests {
+			clearConfigEnv(t)
+			path := writeConfigFile(t, tt.name, tt.content)
+			_, err := LoadConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("%s %q: error = %v, want it to mention %q", tt.name, tt.content, err, tt.want)
+			}
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("LoadConfig() expected error for missing file")
+		}
+	})
+}
@@ -47,6 +47,7 @@
 //   - Recovery: Panic recovery with detailed error logging
 //   - CORS: Cross-origin resource sharing with configurable options
 //   - Timeout: Request timeout handling
+//   - CloudTasks: Cloud Tasks request headers as a typed CloudTask
 //
 // # Logging
 //
@@ -0,0 +1,172 @@
+// Package errs provides errors that carry structured log fields and the stack
+// where they were created.
+//
+// Fields attached anywhere in a wrap chain are emitted by logger.LogError and
+// telemetry.RecordError:
+//
+//	if err := db.Query(ctx, q); err != nil {
+//	    return errs.With(err, "order_id", id)
+//	}
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+)
+
+// maxStackDepth is the number of frames captured for a new error
+const maxStackDepth = 64
+
+// Error is an error with structured fields and an optional captured stack
+type Error struct {
+	msg    string
+	err    error
+	fields []slog.Attr
+	stack  []uintptr
+}
+
+// New returns an error with the given message and key/value fields, capturing the stack
+func New(msg string, args ...any) error {
+	return &Error{msg: msg, fields: toAttrs(args), stack: callers()}
+}
+
+// With attaches key/value fields to err. The stack is captured unless the
+// chain already has one. With returns nil if err is nil.
+func With(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{err: err, fields: toAttrs(args)}
+	if !hasStack(err) {
+		e.stack = callers()
+	}
+	return e
+}
+
+// Wrap annotates err with a message and key/value fields, like
+// fmt.Errorf("msg: %w", err). Wrap returns nil if err is nil.
+func Wrap(err error, msg string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{msg: msg, err: err, fields: toAttrs(args)}
+	if !hasStack(err) {
+		e.stack = callers()
+	}
+	return e
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	switch {
+	case e.err == nil:
+		return e.msg
+	case e.msg == "":
+		return e.err.Error()
+	default:
+		return e.msg + ": " + e.err.Error()
+	}
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Fields returns the fields attached to every error in the chain, innermost
+// first. When a key is set more than once, the outermost value wins.
+func Fields(err error) []slog.Attr {
+	var chain []*Error
+	Walk(err, func(err error) {
+		if e, ok := err.(*Error); ok {
+			chain = append(chain, e)
+		}
+	})
+
+	var fields []slog.Attr
+	index := make(map[string]int)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, a := range chain[i].fields {
+			if j, ok := index[a.Key]; ok {
+				fields[j] = a
+				continue
+			}
+			index[a.Key] = len(fields)
+			fields = append(fields, a)
+		}
+	}
+	return fields
+}
+
+// Stack returns the innermost stack captured in the chain, or nil
+func Stack(err error) []uintptr {
+	var stack []uintptr
+	Walk(err, func(err error) {
+		if e, ok := err.(*Error); ok && e.stack != nil {
+			stack = e.stack
+		}
+	})
+	return stack
+}
+
+// Types returns the dynamic types of the errors in the chain, outermost
+// first, excluding the *Error wrappers added by this package
+func Types(err error) []string {
+	var types []string
+	Walk(err, func(err error) {
+		if _, ok := err.(*Error); !ok {
+			types = append(types, fmt.Sprintf("%T", err))
+		}
+	})
+	return types
+}
+
+// Walk calls fn for err and every error it wraps, depth first. Both
+// Unwrap() error and Unwrap() []error (errors.Join) are followed.
+func Walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		Walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			Walk(e, fn)
+		}
+	}
+}
+
+// hasStack reports whether any error in the chain captured a stack
+func hasStack(err error) bool {
+	var e *Error
+	for err != nil {
+		if !errors.As(err, &e) {
+			return false
+		}
+		if e.stack != nil {
+			return true
+		}
+		err = e.err
+	}
+	return false
+}
+
+// callers captures the stack of the caller of the exported constructor
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers, callers and the constructor
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+// toAttrs normalizes key/value pairs and slog.Attr values the same way slog does
+func toAttrs(args []any) []slog.Attr {
+	if len(args) == 0 {
+		return nil
+	}
+	return slog.Group("", args...).Value.Group()
+}
@@ -0,0 +1,135 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// attrMap returns attributes by key
+func attrMap(attrs []slog.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value.Any()
+	}
+	return m
+}
+
+// stackFunctions returns the function names of a captured stack
+func stackFunctions(pcs []uintptr) []string {
+	var names []string
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		names = append(names, frame.Function)
+		if !more {
+			return names
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	err := New("order not found", "order_id", 42)
+
+	if err.Error() != "order not found" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if got := attrMap(Fields(err))["order_id"]; got != int64(42) {
+		t.Errorf("order_id = %v, want 42", got)
+	}
+	stack := Stack(err)
+	if len(stack) == 0 {
+		t.Fatal("Stack() is empty")
+	}
+	if fn := stackFunctions(stack)[0]; !strings.HasSuffix(fn, ".TestNew") {
+		t.Errorf("top frame = %q, want TestNew", fn)
+	}
+}
+
+func TestWith(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		if With(nil, "k", "v") != nil {
+			t.Error("With(nil) != nil")
+		}
+		if Wrap(nil, "msg") != nil {
+			t.Error("Wrap(nil) != nil")
+		}
+	})
+
+	t.Run("preserves the chain", func(t *testing.T) {
+		err := With(io.EOF, "file", "a.txt")
+		if !errors.Is(err, io.EOF) {
+			t.Error("errors.Is(err, io.EOF) = false")
+		}
+		if err.Error() != io.EOF.Error() {
+			t.Errorf("Error() = %q", err.Error())
+		}
+	})
+
+	t.Run("outermost field wins", func(t *testing.T) {
+		inner := With(io.EOF, "attempt", 1, "file", "a.txt")
+		outer := fmt.Errorf("reading config: %w", With(inner, "attempt", 2))
+
+		fields := Fields(outer)
+		keys := make([]string, len(fields))
+		for i, a := range fields {
+			keys[i] = a.Key
+		}
+		if !slices.Equal(keys, []string{"attempt", "file"}) {
+			t.Errorf("keys = %v", keys)
+		}
+		if got := attrMap(fields)["attempt"]; got != int64(2) {
+			t.Errorf("attempt = %v, want 2", got)
+		}
+	})
+
+	t.Run("keeps the innermost stack", func(t *testing.T) {
+		inner := innerError()
+		outer := With(inner, "k", "v")
+		if outer.(*Error).stack != nil {
+			t.Error("With captured a second stack")
+		}
+		if fn := stackFunctions(Stack(outer))[0]; !strings.HasSuffix(fn, ".innerError") {
+			t.Errorf("top frame = %q, want innerError", fn)
+		}
+	})
+}
+
+func innerError() error {
+	return New("inner")
+}
+
+func TestWrap(t *testing.T) {
+	err := Wrap(io.EOF, "reading header", "offset", 12)
+	if err.Error() != "reading header: EOF" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Error("errors.Is(err, io.EOF) = false")
+	}
+}
+
+func TestTypes(t *testing.T) {
+	err := fmt.Errorf("handler: %w", With(errors.Join(io.EOF, New("x")), "k", "v"))
+
+	got := Types(err)
+	want := []string{"*fmt.wrapError", "*errors.joinError", "*errors.errorString"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+}
+
+func TestWalkJoin(t *testing.T) {
+	a := With(io.EOF, "a", 1)
+	b := With(io.ErrUnexpectedEOF, "b", 2)
+
+	fields := attrMap(Fields(errors.Join(a, b)))
+	if fields["a"] != int64(1) || fields["b"] != int64(2) {
+		t.Errorf("Fields() = %v, want fields from both joined errors", fields)
+	}
+}
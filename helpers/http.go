@@ -7,7 +7,9 @@ import (
 
 	"github.com/sirhco/go-gcp-middleware/logger"
 	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -72,16 +74,14 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
-// ExtractTraceContext extracts OpenTelemetry trace context from HTTP headers
+// ExtractTraceContext extracts OpenTelemetry trace context and baggage from HTTP headers
 func ExtractTraceContext(r *http.Request) context.Context {
-	// Use the OpenTelemetry propagator to extract context from headers
-	return r.Context()
+	return otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 }
 
-// InjectTraceContext injects OpenTelemetry trace context into HTTP headers
+// InjectTraceContext injects OpenTelemetry trace context and baggage into HTTP headers
 func InjectTraceContext(ctx context.Context, req *http.Request) {
-	// Use the OpenTelemetry propagator to inject context into headers
-	// This would typically use otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 }
 
 // TraceHTTPClient wraps an HTTP client with tracing
@@ -127,9 +127,11 @@ func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 		)
 	}
 
-	// Perform request
+	// Perform request, propagating the span and baggage on a copy of the
+	// request since a RoundTripper must not modify the caller's
 	start := time.Now()
-	req = req.WithContext(ctx)
+	req = req.Clone(ctx)
+	InjectTraceContext(ctx, req)
 	resp, err := t.base.RoundTrip(req)
 	duration := time.Since(start)
 
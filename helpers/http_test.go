@@ -8,6 +8,8 @@ import (
 
 	"github.com/sirhco/go-gcp-middleware/logger"
 	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHTTPMiddleware(t *testing.T) {
@@ -84,21 +86,43 @@ func TestResponseWriter(t *testing.T) {
 	})
 }
 
+// usePropagator installs the default propagator for the duration of the test
+func usePropagator(t *testing.T) {
+	t.Helper()
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(telemetry.NewPropagator())
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+}
+
 func TestExtractTraceContext(t *testing.T) {
+	usePropagator(t)
+
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("baggage", "tenant.id=acme")
 
 	ctx := ExtractTraceContext(req)
-	if ctx == nil {
-		t.Error("Expected non-nil context")
+	if got := trace.SpanContextFromContext(ctx).TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %q", got)
+	}
+	if got := telemetry.GetBaggage(ctx, "tenant.id"); got != "acme" {
+		t.Errorf("baggage tenant.id = %q", got)
 	}
 }
 
 func TestInjectTraceContext(t *testing.T) {
-	ctx := context.Background()
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	usePropagator(t)
 
-	// Should not panic
+	ctx, err := telemetry.SetBaggage(context.Background(), "tenant.id", "acme")
+	if err != nil {
+		t.Fatalf("SetBaggage() error = %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	InjectTraceContext(ctx, req)
+
+	if got := req.Header.Get("baggage"); got != "tenant.id=acme" {
+		t.Errorf("baggage header = %q", got)
+	}
 }
 
 func TestTraceHTTPClient(t *testing.T) {
@@ -175,6 +199,38 @@ func TestTracingTransport(t *testing.T) {
 	}
 }
 
+func TestTracingTransportPropagatesBaggage(t *testing.T) {
+	usePropagator(t)
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("baggage")
+	}))
+	defer server.Close()
+
+	ctx, err := telemetry.SetBaggage(context.Background(), "tenant.id", "acme")
+	if err != nil {
+		t.Fatalf("SetBaggage() error = %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := TraceHTTPClient(&http.Client{}, nil).Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if received != "tenant.id=acme" {
+		t.Errorf("server received baggage %q, want tenant.id=acme", received)
+	}
+	if req.Header.Get("baggage") != "" {
+		t.Error("transport modified the caller's request headers")
+	}
+}
+
 func TestTracingTransportError(t *testing.T) {
 	ctx := context.Background()
 
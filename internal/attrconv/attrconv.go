@@ -0,0 +1,57 @@
+// Package attrconv converts slog values to OpenTelemetry attributes for the
+// logger and telemetry packages.
+package attrconv
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// FromSlog converts a resolved slog value to the closest attribute type
+func FromSlog(key string, v slog.Value) attribute.KeyValue {
+	switch v.Kind() {
+	case slog.KindString:
+		return attribute.String(key, v.String())
+	case slog.KindInt64:
+		return attribute.Int64(key, v.Int64())
+	case slog.KindUint64:
+		if u := v.Uint64(); u <= math.MaxInt64 {
+			return attribute.Int64(key, int64(u))
+		}
+		return attribute.String(key, v.String())
+	case slog.KindFloat64:
+		return attribute.Float64(key, v.Float64())
+	case slog.KindBool:
+		return attribute.Bool(key, v.Bool())
+	case slog.KindDuration:
+		return attribute.String(key, v.Duration().String())
+	case slog.KindTime:
+		return attribute.String(key, v.Time().Format(time.RFC3339Nano))
+	case slog.KindAny:
+	default:
+		return attribute.String(key, v.String())
+	}
+
+	switch val := v.Any().(type) {
+	case error:
+		return attribute.String(key, val.Error())
+	case []string:
+		return attribute.StringSlice(key, val)
+	case []int:
+		return attribute.IntSlice(key, val)
+	case []int64:
+		return attribute.Int64Slice(key, val)
+	case []float64:
+		return attribute.Float64Slice(key, val)
+	case []bool:
+		return attribute.BoolSlice(key, val)
+	case fmt.Stringer:
+		return attribute.String(key, val.String())
+	default:
+		return attribute.String(key, v.String())
+	}
+}
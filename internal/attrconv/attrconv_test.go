@@ -0,0 +1,43 @@
+package attrconv
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestFromSlog(t *testing.T) {
+	tests := []struct {
+		name string
+		v    slog.Value
+		want attribute.Value
+	}{
+		{"string", slog.StringValue("a"), attribute.StringValue("a")},
+		{"int", slog.IntValue(42), attribute.Int64Value(42)},
+		{"uint", slog.Uint64Value(7), attribute.Int64Value(7)},
+		{"uint overflow", slog.Uint64Value(math.MaxUint64), attribute.StringValue("18446744073709551615")},
+		{"float", slog.Float64Value(1.5), attribute.Float64Value(1.5)},
+		{"bool", slog.BoolValue(true), attribute.BoolValue(true)},
+		{"duration", slog.DurationValue(1500 * time.Millisecond), attribute.StringValue("1.5s")},
+		{"time", slog.TimeValue(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)), attribute.StringValue("2026-01-01T12:00:00Z")},
+		{"error", slog.AnyValue(errors.New("boom")), attribute.StringValue("boom")},
+		{"strings", slog.AnyValue([]string{"a", "b"}), attribute.StringSliceValue([]string{"a", "b"})},
+		{"ints", slog.AnyValue([]int{1, 2}), attribute.IntSliceValue([]int{1, 2})},
+		{"stringer", slog.AnyValue(netip.MustParseAddr("10.0.0.1")), attribute.StringValue("10.0.0.1")},
+		{"map", slog.AnyValue(map[string]int{"a": 1}), attribute.StringValue("map[a:1]")},
+		{"group", slog.GroupValue(slog.Int("a", 1)), attribute.StringValue("[a=1]")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromSlog("k", tt.v)
+			if got.Key != "k" || got.Value != tt.want {
+				t.Errorf("FromSlog() = %v, want %v", got.Value.Emit(), tt.want.Emit())
+			}
+		})
+	}
+}
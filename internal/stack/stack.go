@@ -0,0 +1,30 @@
+// Package stack renders captured program counters for the logger and
+// telemetry packages.
+package stack
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Format renders pcs in the runtime.Stack layout that Cloud Error Reporting
+// parses for Go, headed by msg
+func Format(msg string, pcs []uintptr) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	b.WriteString("\n\ngoroutine 1 [running]:\n")
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			fmt.Fprintf(&b, "%s(...)\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
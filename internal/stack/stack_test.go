@@ -0,0 +1,26 @@
+package stack
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(1, pcs)
+
+	got := Format("boom", pcs[:n])
+	if !strings.HasPrefix(got, "boom\n\ngoroutine 1 [running]:\n") {
+		t.Errorf("Format() header = %q", got)
+	}
+	if !strings.Contains(got, "stack.TestFormat(...)\n\t") || !strings.Contains(got, "stack_test.go:") {
+		t.Errorf("Format() missing caller frame: %q", got)
+	}
+}
+
+func TestFormatEmpty(t *testing.T) {
+	if got := Format("boom", nil); got != "boom\n\ngoroutine 1 [running]:\n" {
+		t.Errorf("Format(nil) = %q", got)
+	}
+}
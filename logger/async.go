@@ -0,0 +1,220 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what happens when the async queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the caller wait for space in the queue
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued record
+	OverflowDropOldest
+	// OverflowDropDebugFirst discards queued debug records first. If none are
+	// queued, an incoming record below warn is dropped; otherwise the oldest
+	// queued record is discarded.
+	OverflowDropDebugFirst
+)
+
+// AsyncConfig configures asynchronous, buffered log writing
+type AsyncConfig struct {
+	QueueSize int            // Maximum number of queued records (default: 1024)
+	Overflow  OverflowPolicy // Behavior when the queue is full (default: OverflowBlock)
+}
+
+// SetDefaults sets reasonable defaults for the async config
+func (c *AsyncConfig) SetDefaults() {
+	if c.QueueSize <= 0 {
+		c.QueueSize = 1024
+	}
+}
+
+// AsyncStats reports counters for the async log queue
+type AsyncStats struct {
+	Queued  int    // Records currently waiting to be written
+	Written uint64 // Records handed to the sinks
+	Dropped uint64 // Records discarded by the overflow policy
+}
+
+// asyncEntry is a record waiting to be written by the worker
+type asyncEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	rec     slog.Record
+}
+
+// asyncQueue is a bounded queue drained by a single worker goroutine.
+// It is shared by an asyncHandler and every handler derived from it.
+type asyncQueue struct {
+	config AsyncConfig
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	entries  []asyncEntry
+	closed   bool
+	done     chan struct{}
+
+	written atomic.Uint64
+	dropped atomic.Uint64
+}
+
+func newAsyncQueue(config AsyncConfig) *asyncQueue {
+	config.SetDefaults()
+	q := &asyncQueue{
+		config:  config,
+		entries: make([]asyncEntry, 0, config.QueueSize),
+		done:    make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// enqueue adds an entry, applying the overflow policy when the queue is full.
+// It reports false if the queue is closed and the caller must write synchronously.
+func (q *asyncQueue) enqueue(entry asyncEntry) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for !q.closed && len(q.entries) >= q.config.QueueSize {
+		switch q.config.Overflow {
+		case OverflowDropOldest:
+			q.removeAt(0)
+		case OverflowDropDebugFirst:
+			if !q.dropDebug(entry.rec.Level) {
+				q.dropped.Add(1)
+				return true
+			}
+		default:
+			q.notFull.Wait()
+		}
+	}
+	if q.closed {
+		return false
+	}
+
+	q.entries = append(q.entries, entry)
+	q.notEmpty.Signal()
+	return true
+}
+
+// dropDebug frees a slot for an incoming record at the given level. It reports
+// false if the incoming record itself should be dropped instead.
+func (q *asyncQueue) dropDebug(level slog.Level) bool {
+	for i, e := range q.entries {
+		if e.rec.Level < slog.LevelInfo {
+			q.removeAt(i)
+			return true
+		}
+	}
+	if level < slog.LevelWarn {
+		return false
+	}
+	q.removeAt(0)
+	return true
+}
+
+// removeAt drops the queued entry at index i; the caller must hold q.mu
+func (q *asyncQueue) removeAt(i int) {
+	q.entries = append(q.entries[:i], q.entries[i+1:]...)
+	q.dropped.Add(1)
+}
+
+// run writes queued entries until the queue is closed and empty
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for len(q.entries) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if len(q.entries) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		entry := q.entries[0]
+		q.entries[0] = asyncEntry{}
+		q.entries = q.entries[1:]
+		q.notFull.Signal()
+		q.mu.Unlock()
+
+		// Sink errors cannot be returned to the caller once queued
+		_ = entry.handler.Handle(entry.ctx, entry.rec)
+		q.written.Add(1)
+	}
+}
+
+// close stops accepting records and waits for the worker to drain the queue
+// or for ctx to be done
+func (q *asyncQueue) close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		q.notEmpty.Broadcast()
+		q.notFull.Broadcast()
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *asyncQueue) stats() AsyncStats {
+	q.mu.Lock()
+	queued := len(q.entries)
+	q.mu.Unlock()
+
+	return AsyncStats{
+		Queued:  queued,
+		Written: q.written.Load(),
+		Dropped: q.dropped.Load(),
+	}
+}
+
+// asyncHandler hands records to a background worker so callers never wait on slow sinks
+type asyncHandler struct {
+	handler slog.Handler
+	queue   *asyncQueue
+}
+
+func newAsyncHandler(handler slog.Handler, config AsyncConfig) *asyncHandler {
+	return &asyncHandler{handler: handler, queue: newAsyncQueue(config)}
+}
+
+func (h *asyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *asyncHandler) Handle(ctx context.Context, rec slog.Record) error {
+	entry := asyncEntry{
+		// The request may finish before the record is written; keep values but drop cancellation
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.handler,
+		rec:     rec.Clone(),
+	}
+	if h.queue.enqueue(entry) {
+		return nil
+	}
+
+	// Queue is closed; write synchronously so late records are not lost
+	return h.handler.Handle(ctx, rec)
+}
+
+func (h *asyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &asyncHandler{handler: h.handler.WithAttrs(attrs), queue: h.queue}
+}
+
+func (h *asyncHandler) WithGroup(name string) slog.Handler {
+	return &asyncHandler{handler: h.handler.WithGroup(name), queue: h.queue}
+}
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler collects records and optionally blocks until released
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+	gate    chan struct{}
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, rec slog.Record) error {
+	if h.gate != nil {
+		<-h.gate
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, rec)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+func (h *recordingHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	msgs := make([]string, len(h.records))
+	for i, rec := range h.records {
+		msgs[i] = rec.Message
+	}
+	return msgs
+}
+
+// waitQueued waits until the worker has taken the first record and the queue holds n records
+func waitQueued(t *testing.T, q *asyncQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for q.stats().Queued != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue length = %d, want %d", q.stats().Queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAsyncHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes in order and drains on shutdown", func(t *testing.T) {
+		sink := &recordingHandler{}
+		handler := newAsyncHandler(sink, AsyncConfig{QueueSize: 16})
+		log := &Logger{logger: slog.New(handler), pipeline: &pipeline{level: new(slog.LevelVar), async: handler.queue}}
+
+		log.InfoContext(ctx, "one")
+		log.InfoContext(ctx, "two")
+		log.InfoContext(ctx, "three")
+
+		if err := log.Shutdown(ctx); err != nil {
+			t.Fatalf("Shutdown() error = %v", err)
+		}
+
+		msgs := sink.messages()
+		if len(msgs) != 3 || msgs[0] != "one" || msgs[2] != "three" {
+			t.Errorf("messages = %v, want [one two three]", msgs)
+		}
+		if stats := log.AsyncStats(); stats.Written != 3 || stats.Dropped != 0 {
+			t.Errorf("AsyncStats() = %+v, want 3 written and 0 dropped", stats)
+		}
+
+		// Records logged after shutdown are written synchronously
+		log.InfoContext(ctx, "late")
+		if msgs := sink.messages(); msgs[len(msgs)-1] != "late" {
+			t.Errorf("late record was not written: %v", msgs)
+		}
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		sink := &recordingHandler{gate: make(chan struct{})}
+		handler := newAsyncHandler(sink, AsyncConfig{QueueSize: 2, Overflow: OverflowDropOldest})
+		log := slog.New(handler)
+
+		log.Info("in-flight")
+		waitQueued(t, handler.queue, 0)
+		log.Info("a")
+		log.Info("b")
+		log.Info("c")
+
+		close(sink.gate)
+		if err := handler.queue.close(ctx); err != nil {
+			t.Fatalf("close() error = %v", err)
+		}
+
+		msgs := sink.messages()
+		if len(msgs) != 3 || msgs[1] != "b" || msgs[2] != "c" {
+			t.Errorf("messages = %v, want [in-flight b c]", msgs)
+		}
+		if dropped := handler.queue.stats().Dropped; dropped != 1 {
+			t.Errorf("Dropped = %d, want 1", dropped)
+		}
+	})
+
+	t.Run("drop debug first", func(t *testing.T) {
+		sink := &recordingHandler{gate: make(chan struct{})}
+		handler := newAsyncHandler(sink, AsyncConfig{QueueSize: 2, Overflow: OverflowDropDebugFirst})
+		log := slog.New(handler)
+
+		log.Info("in-flight")
+		waitQueued(t, handler.queue, 0)
+		log.Debug("debug")
+		log.Info("info")
+		log.Info("dropped-info") // evicts the queued debug record
+		log.Info("rejected")     // no debug left, incoming info is dropped
+		log.Warn("warn")         // warn evicts the oldest record
+
+		close(sink.gate)
+		if err := handler.queue.close(ctx); err != nil {
+			t.Fatalf("close() error = %v", err)
+		}
+
+		msgs := sink.messages()
+		want := []string{"in-flight", "dropped-info", "warn"}
+		if len(msgs) != len(want) {
+			t.Fatalf("messages = %v, want %v", msgs, want)
+		}
+		for i := range want {
+			if msgs[i] != want[i] {
+				t.Errorf("messages = %v, want %v", msgs, want)
+				break
+			}
+		}
+		if dropped := handler.queue.stats().Dropped; dropped != 3 {
+			t.Errorf("Dropped = %d, want 3", dropped)
+		}
+	})
+
+	t.Run("shutdown honors context deadline", func(t *testing.T) {
+		sink := &recordingHandler{gate: make(chan struct{})}
+		handler := newAsyncHandler(sink, AsyncConfig{QueueSize: 4})
+		slog.New(handler).Info("stuck")
+
+		shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if err := handler.queue.close(shutdownCtx); err == nil {
+			t.Error("close() expected deadline error while the sink is blocked")
+		}
+		close(sink.gate)
+	})
+}
+
+func TestSetLevelWithAsync(t *testing.T) {
+	log, err := NewLogger(context.Background(), Config{
+		ServiceName: "test-service",
+		Level:       LevelInfo,
+		Async:       &AsyncConfig{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer log.Close()
+
+	queue := log.pipeline.async
+	log.SetLevel(LevelDebug)
+	if log.pipeline.async != queue {
+		t.Error("SetLevel() replaced the async queue")
+	}
+	if !log.logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("SetLevel(LevelDebug) did not enable debug records")
+	}
+}
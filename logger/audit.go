@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultAuditLogName is the log audit records are written to
+	defaultAuditLogName = "audit"
+
+	// auditGroup is the attribute group holding the audit record
+	auditGroup = "audit"
+
+	// auditMessage is the message of every audit log entry
+	auditMessage = "audit event"
+)
+
+// AuditOutcome is the result of an audited action
+type AuditOutcome string
+
+// Audit outcomes
+const (
+	OutcomeSuccess AuditOutcome = "success"
+	OutcomeFailure AuditOutcome = "failure"
+	OutcomeDenied  AuditOutcome = "denied"
+)
+
+// AuditConfig configures audit logging
+type AuditConfig struct {
+	LogName   string // Log audit records are written to (default: "audit")
+	HashChain bool   // Link records with seq, prev_hash and hash so gaps and edits can be detected
+}
+
+// SetDefaults applies default values to unset fields
+func (c *AuditConfig) SetDefaults() {
+	if c.LogName == "" {
+		c.LogName = defaultAuditLogName
+	}
+}
+
+// AuditEvent describes who did what to which resource, with what result
+type AuditEvent struct {
+	Principal string // Acting identity; empty uses the principal from the context
+	Action    string // e.g. "orders.delete"
+	Resource  string // e.g. "orders/1234"
+	Outcome   AuditOutcome
+	Metadata  map[string]any
+}
+
+// AuditRecord is the audit group of a written entry. With HashChain enabled,
+// Hash covers every other field and PrevHash links to the previous record.
+type AuditRecord struct {
+	Seq       uint64         `json:"seq"`
+	PrevHash  string         `json:"prev_hash,omitempty"`
+	Hash      string         `json:"hash,omitempty"`
+	Timestamp string         `json:"timestamp"`
+	Principal string         `json:"principal"`
+	Action    string         `json:"action"`
+	Resource  string         `json:"resource"`
+	Outcome   AuditOutcome   `json:"outcome"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+}
+
+// principalKey is the context key for the authenticated principal
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated principal, used
+// by Audit when an event has none
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored with WithPrincipal
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	p, ok := ctx.Value(principalKey{}).(string)
+	return p, ok && p != ""
+}
+
+// auditLog writes audit records through a sink chain without rate limiting or
+// buffering. The mutex keeps sequence numbers in write order.
+type auditLog struct {
+	mu        sync.Mutex
+	handler   slog.Handler
+	redactor  *Redactor
+	hashChain bool
+	seq       uint64
+	prevHash  string
+}
+
+// newAuditLog builds the audit sinks from config, writing under the audit log name
+func newAuditLog(config Config, p *pipeline) *auditLog {
+	auditConfig := AuditConfig{}
+	if config.Audit != nil {
+		auditConfig = *config.Audit
+	}
+	auditConfig.SetDefaults()
+
+	// Audit records ignore SetLevel and per-sink levels
+	config.LogName = auditConfig.LogName
+	config.Console.Level = minLevel
+	config.GCP.Level = minLevel
+	handler := createSinkHandler(config, &pipeline{level: p.level, cloud: p.cloud})
+	handler = handler.WithAttrs([]slog.Attr{slog.String(logNameKey, auditConfig.LogName)})
+
+	a := &auditLog{handler: handler, hashChain: auditConfig.HashChain}
+	if config.Redact != nil {
+		a.redactor = NewRedactor(*config.Redact)
+	}
+	return a
+}
+
+// write builds the next record for ev and writes it
+func (a *auditLog) write(ctx context.Context, ev AuditEvent, attrs []any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	rec := AuditRecord{
+		Timestamp: now.UTC().Format(time.RFC3339Nano),
+		Principal: ev.Principal,
+		Action:    ev.Action,
+		Resource:  ev.Resource,
+		Outcome:   ev.Outcome,
+		Metadata:  a.redactMetadata(ev.Metadata),
+	}
+	if a.hashChain {
+		rec.Seq = a.seq + 1
+		rec.PrevHash = a.prevHash
+		hash, err := AuditHash(rec)
+		if err != nil {
+			return fmt.Errorf("hashing audit record: %w", err)
+		}
+		rec.Hash = hash
+	}
+
+	r := slog.NewRecord(now, slog.LevelInfo, auditMessage, 0)
+	r.Add(attrs...)
+	r.AddAttrs(rec.attr())
+	if err := a.handler.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	if a.hashChain {
+		a.seq = rec.Seq
+		a.prevHash = rec.Hash
+	}
+	return nil
+}
+
+// redactMetadata scrubs metadata before it is hashed, so the logged record verifies
+func (a *auditLog) redactMetadata(metadata map[string]any) map[string]any {
+	if a.redactor == nil || len(metadata) == 0 {
+		return metadata
+	}
+	out := make(map[string]any, len(metadata))
+	for k, v := range metadata {
+		out[k] = a.redactor.RedactAttr(slog.Any(k, v)).Value.Any()
+	}
+	return out
+}
+
+// attr returns the record as the audit attribute group
+func (r AuditRecord) attr() slog.Attr {
+	attrs := []any{
+		slog.String("timestamp", r.Timestamp),
+		slog.String("principal", r.Principal),
+		slog.String("action", r.Action),
+		slog.String("resource", r.Resource),
+		slog.String("outcome", string(r.Outcome)),
+	}
+	if len(r.Metadata) > 0 {
+		attrs = append(attrs, slog.Any("metadata", r.Metadata))
+	}
+	if r.Hash != "" {
+		attrs = append(attrs,
+			slog.Uint64("seq", r.Seq),
+			slog.String("prev_hash", r.PrevHash),
+			slog.String("hash", r.Hash),
+		)
+	}
+	return slog.Group(auditGroup, attrs...)
+}
+
+// AuditHash returns the hex SHA-256 of the record's JSON encoding without its Hash
+func AuditHash(r AuditRecord) (string, error) {
+	r.Hash = ""
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyAuditChain checks that records, in write order, form an unbroken hash
+// chain: sequence numbers are consecutive, each PrevHash matches the previous
+// Hash, and every Hash matches its record
+func VerifyAuditChain(records []AuditRecord) error {
+	for i, r := range records {
+		hash, err := AuditHash(r)
+		if err != nil {
+			return fmt.Errorf("audit record %d: %w", r.Seq, err)
+		}
+		if hash != r.Hash {
+			return fmt.Errorf("audit record %d: hash mismatch", r.Seq)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := records[i-1]
+		if r.Seq != prev.Seq+1 {
+			return fmt.Errorf("audit gap: records %d to %d are missing", prev.Seq+1, r.Seq-1)
+		}
+		if r.PrevHash != prev.Hash {
+			return fmt.Errorf("audit record %d: prev_hash does not match record %d", r.Seq, prev.Seq)
+		}
+	}
+	return nil
+}
+
+// Audit writes an audit record to the audit log. Records are never rate
+// limited or buffered, and carry trace correlation from ctx.
+func (l *Logger) Audit(ctx context.Context, ev AuditEvent) error {
+	if ev.Principal == "" {
+		ev.Principal, _ = PrincipalFromContext(ctx)
+	}
+
+	attrs := addTraceContext(ctx, l.config.ProjectID, nil)
+	ctx = l.enrichContext(ctx)
+
+	if l.pipeline == nil || l.pipeline.audit == nil {
+		l.logger.LogAttrs(ctx, slog.LevelInfo, auditMessage, AuditRecord{
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+			Principal: ev.Principal,
+			Action:    ev.Action,
+			Resource:  ev.Resource,
+			Outcome:   ev.Outcome,
+			Metadata:  ev.Metadata,
+		}.attr())
+		return nil
+	}
+	return l.pipeline.audit.write(ctx, ev, attrs)
+}
+
+// Audit writes an audit record using the global logger
+func Audit(ctx context.Context, ev AuditEvent) error {
+	return Global().Audit(ctx, ev)
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// auditRecords decodes the audit groups of the entries in buf
+func auditRecords(t *testing.T, buf *bytes.Buffer) []AuditRecord {
+	t.Helper()
+	var records []AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry struct {
+			Audit *AuditRecord `json:"audit"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode log entry %q: %v", line, err)
+		}
+		if entry.Audit != nil {
+			records = append(records, *entry.Audit)
+		}
+	}
+	return records
+}
+
+func TestAudit(t *testing.T) {
+	t.Run("record shape", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ProjectID: "proj", ServiceName: "svc"}, &buf)
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+		ctx = WithPrincipal(ctx, "user:jane@example.com")
+
+		err := log.Audit(ctx, AuditEvent{
+			Action:   "orders.delete",
+			Resource: "orders/1234",
+			Outcome:  OutcomeSuccess,
+			Metadata: map[string]any{"reason": "duplicate"},
+		})
+		if err != nil {
+			t.Fatalf("Audit() error = %v", err)
+		}
+
+		entry := decodeEntry(t, &buf)
+		if entry["log_name"] != defaultAuditLogName {
+			t.Errorf("log_name = %v, want %q", entry["log_name"], defaultAuditLogName)
+		}
+		if entry["logging.googleapis.com/trace"] != "projects/proj/traces/"+traceID.String() {
+			t.Errorf("trace = %v", entry["logging.googleapis.com/trace"])
+		}
+		audit, _ := entry["audit"].(map[string]any)
+		want := map[string]any{
+			"principal": "user:jane@example.com",
+			"action":    "orders.delete",
+			"resource":  "orders/1234",
+			"outcome":   "success",
+		}
+		for k, v := range want {
+			if audit[k] != v {
+				t.Errorf("audit.%s = %v, want %v", k, audit[k], v)
+			}
+		}
+		if _, ok := audit["hash"]; ok {
+			t.Error("hash written without HashChain")
+		}
+	})
+
+	t.Run("explicit principal wins", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ServiceName: "svc"}, &buf)
+
+		ctx := WithPrincipal(context.Background(), "user:jane@example.com")
+		_ = log.Audit(ctx, AuditEvent{Principal: "serviceAccount:cron", Action: "cleanup"})
+
+		if got := auditRecords(t, &buf)[0].Principal; got != "serviceAccount:cron" {
+			t.Errorf("principal = %q", got)
+		}
+	})
+
+	t.Run("ignores level and rate limiting", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{
+			ServiceName: "svc",
+			Level:       LevelError,
+			RateLimit:   &RateLimitConfig{Burst: 1},
+		}, &buf)
+
+		for range 5 {
+			if err := log.Audit(context.Background(), AuditEvent{Action: "read"}); err != nil {
+				t.Fatalf("Audit() error = %v", err)
+			}
+		}
+		if got := len(auditRecords(t, &buf)); got != 5 {
+			t.Errorf("got %d audit records, want 5", got)
+		}
+	})
+
+	t.Run("redacts metadata", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ServiceName: "svc", Redact: DefaultRedactConfig()}, &buf)
+
+		_ = log.Audit(context.Background(), AuditEvent{Action: "login", Metadata: map[string]any{"password": "hunter2"}})
+		if strings.Contains(buf.String(), "hunter2") {
+			t.Errorf("metadata was not redacted: %s", buf.String())
+		}
+	})
+
+	t.Run("custom log name", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ServiceName: "svc", Audit: &AuditConfig{LogName: "security"}}, &buf)
+
+		_ = log.Audit(context.Background(), AuditEvent{Action: "login"})
+		if got := decodeEntry(t, &buf)["log_name"]; got != "security" {
+			t.Errorf("log_name = %v, want security", got)
+		}
+	})
+}
+
+func TestAuditHashChain(t *testing.T) {
+	var buf bytes.Buffer
+	log := newTestLogger(t, Config{ServiceName: "svc", Audit: &AuditConfig{HashChain: true}}, &buf)
+
+	for _, action := range []string{"create", "update", "delete"} {
+		err := log.WithLogName("other").Audit(context.Background(), AuditEvent{
+			Action:   action,
+			Outcome:  OutcomeSuccess,
+			Metadata: map[string]any{"count": 3},
+		})
+		if err != nil {
+			t.Fatalf("Audit() error = %v", err)
+		}
+	}
+
+	records := auditRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	for i, r := range records {
+		if r.Seq != uint64(i+1) {
+			t.Errorf("record %d seq = %d", i, r.Seq)
+		}
+	}
+	if err := VerifyAuditChain(records); err != nil {
+		t.Errorf("VerifyAuditChain() error = %v", err)
+	}
+
+	t.Run("detects gaps", func(t *testing.T) {
+		err := VerifyAuditChain([]AuditRecord{records[0], records[2]})
+		if err == nil || !strings.Contains(err.Error(), "gap") {
+			t.Errorf("VerifyAuditChain() error = %v, want gap", err)
+		}
+	})
+
+	t.Run("detects edits", func(t *testing.T) {
+		edited := append([]AuditRecord(nil), records...)
+		edited[1].Outcome = OutcomeDenied
+		if err := VerifyAuditChain(edited); err == nil {
+			t.Error("VerifyAuditChain() accepted an edited record")
+		}
+	})
+}
+
+func TestAuditGlobalFallback(t *testing.T) {
+	log := &Logger{logger: slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))}
+	if err := log.Audit(context.Background(), AuditEvent{Action: "noop"}); err != nil {
+		t.Errorf("Audit() error = %v", err)
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Error("PrincipalFromContext() ok on empty context")
+	}
+	ctx := WithPrincipal(context.Background(), "user:a@example.com")
+	if p, ok := PrincipalFromContext(ctx); !ok || p != "user:a@example.com" {
+		t.Errorf("PrincipalFromContext() = %q, %v", p, ok)
+	}
+}
@@ -0,0 +1,507 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"github.com/chainguard-dev/clog/gcp"
+	gcpdetector "go.opentelemetry.io/contrib/detectors/gcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+	mrpb "google.golang.org/genproto/googleapis/api/monitoredres"
+)
+
+// logNameKey is the attribute WithLogName sets; the Cloud Logging sink routes on it
+const logNameKey = "log_name"
+
+const (
+	// specialFieldPrefix marks the structured logging fields Cloud Logging
+	// lifts out of stdout JSON, such as logging.googleapis.com/trace
+	specialFieldPrefix = "logging.googleapis.com/"
+
+	// httpRequestKey is the payload field LogHTTPRequest fills
+	httpRequestKey = "httpRequest"
+)
+
+// CloudLoggingConfig configures the Cloud Logging API sink. Unlike the stderr
+// handler, entries written through the API keep their real log name, so
+// WithLogName("audit") produces projects/{ProjectID}/logs/audit.
+type CloudLoggingConfig struct {
+	// Resource is the monitored resource attached to every entry.
+	// When nil it is detected from the GCP environment, falling back to "global".
+	Resource *mrpb.MonitoredResource
+	// Labels are added to every entry
+	Labels map[string]string
+
+	// Batching: entries are buffered and written together when either threshold is reached
+	DelayThreshold      time.Duration // Maximum time an entry is buffered (default: 1s)
+	EntryCountThreshold int           // Maximum entries per write (default: 1000)
+	BufferedByteLimit   int           // Maximum bytes buffered before entries are dropped (default: library default)
+
+	// ClientOptions are passed to the Cloud Logging client, e.g. a custom endpoint
+	ClientOptions []option.ClientOption
+	// OnError receives asynchronous write errors after retries are exhausted
+	OnError func(error)
+}
+
+// SetDefaults sets reasonable defaults for the Cloud Logging config
+func (c *CloudLoggingConfig) SetDefaults() {
+	if c.DelayThreshold <= 0 {
+		c.DelayThreshold = time.Second
+	}
+	if c.EntryCountThreshold <= 0 {
+		c.EntryCountThreshold = 1000
+	}
+}
+
+// cloudLoggingSink owns the Cloud Logging client and one batching logger per log name
+type cloudLoggingSink struct {
+	client    *logging.Client
+	projectID string
+	opts      []logging.LoggerOption
+
+	mu      sync.RWMutex
+	loggers map[string]*logging.Logger
+	closed  bool
+}
+
+// newCloudLoggingSink creates the Cloud Logging client for the given project
+func newCloudLoggingSink(ctx context.Context, projectID string, config CloudLoggingConfig) (*cloudLoggingSink, error) {
+	config.SetDefaults()
+
+	client, err := logging.NewClient(ctx, projectID, config.ClientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Logging client: %w", err)
+	}
+	if config.OnError != nil {
+		client.OnError = config.OnError
+	}
+
+	resource := config.Resource
+	if resource == nil {
+		resource = detectMonitoredResource(ctx, projectID)
+	}
+
+	opts := []logging.LoggerOption{
+		logging.CommonResource(resource),
+		logging.DelayThreshold(config.DelayThreshold),
+		logging.EntryCountThreshold(config.EntryCountThreshold),
+	}
+	if len(config.Labels) > 0 {
+		opts = append(opts, logging.CommonLabels(config.Labels))
+	}
+	if config.BufferedByteLimit > 0 {
+		opts = append(opts, logging.BufferedByteLimit(config.BufferedByteLimit))
+	}
+
+	return &cloudLoggingSink{
+		client:    client,
+		projectID: projectID,
+		opts:      opts,
+		loggers:   make(map[string]*logging.Logger),
+	}, nil
+}
+
+// log writes an entry to the batching logger for a log name, creating it on
+// first use. Entries logged after the sink is closed are dropped.
+func (s *cloudLoggingSink) log(logName string, entry logging.Entry) {
+	s.mu.RLock()
+	lg, ok := s.loggers[logName]
+	if ok && !s.closed {
+		lg.Log(entry)
+		s.mu.RUnlock()
+		return
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	lg, ok = s.loggers[logName]
+	if !ok {
+		lg = s.client.Logger(logName, s.opts...)
+		s.loggers[logName] = lg
+	}
+	lg.Log(entry)
+}
+
+// close flushes buffered entries and closes the client, waiting until ctx is done
+func (s *cloudLoggingSink) close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() { done <- s.client.Close() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to close Cloud Logging client: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cloudLoggingHandler converts slog records into Cloud Logging entries
+type cloudLoggingHandler struct {
+	sink    *cloudLoggingSink
+	logName string
+	level   slog.Leveler
+	attrs   []slog.Attr
+	groups  []string
+}
+
+func newCloudLoggingHandler(sink *cloudLoggingSink, logName string, level slog.Leveler) *cloudLoggingHandler {
+	return &cloudLoggingHandler{sink: sink, logName: logName, level: level}
+}
+
+func (h *cloudLoggingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *cloudLoggingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	// Attributes from WithAttrs sit at the top level; record attributes belong to the open groups
+	payload := make(map[string]any, len(h.attrs)+rec.NumAttrs()+1)
+	for _, a := range h.attrs {
+		addPayloadAttr(payload, a)
+	}
+
+	target := payload
+	for _, g := range h.groups {
+		group, ok := target[g].(map[string]any)
+		if !ok {
+			group = make(map[string]any)
+			target[g] = group
+		}
+		target = group
+	}
+	rec.Attrs(func(a slog.Attr) bool {
+		addPayloadAttr(target, a)
+		return true
+	})
+	payload["message"] = rec.Message
+
+	entry := logging.Entry{
+		Timestamp: rec.Time,
+		Severity:  cloudLoggingSeverity(rec.Level),
+		Payload:   payload,
+	}
+
+	// The special logging.googleapis.com/* fields are for the stdout agent;
+	// the API carries them, and httpRequest, as entry fields
+	for key := range payload {
+		if strings.HasPrefix(key, specialFieldPrefix) {
+			delete(payload, key)
+		}
+	}
+	if req, ok := payload[httpRequestKey].(map[string]any); ok {
+		if hr := httpRequestFromPayload(req); hr != nil {
+			entry.HTTPRequest = hr
+			delete(payload, httpRequestKey)
+		}
+	}
+	h.addTrace(ctx, &entry)
+	if rec.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{rec.PC}).Next()
+		entry.SourceLocation = &loggingpb.LogEntrySourceLocation{
+			File:     frame.File,
+			Line:     int64(frame.Line),
+			Function: frame.Function,
+		}
+	}
+
+	// Entries logged after shutdown only reach the local sinks
+	h.sink.log(h.logName, entry)
+	return nil
+}
+
+// addTrace correlates the entry with the active span, or with the trace set by clog/gcp
+func (h *cloudLoggingHandler) addTrace(ctx context.Context, entry *logging.Entry) {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		entry.Trace = fmt.Sprintf("projects/%s/traces/%s", h.sink.projectID, sc.TraceID().String())
+		entry.SpanID = sc.SpanID().String()
+		entry.TraceSampled = sc.IsSampled()
+		return
+	}
+	entry.Trace = gcp.TraceFromContext(ctx)
+}
+
+func (h *cloudLoggingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	if len(h.groups) == 0 {
+		// WithLogName adds log_name; route the derived handler to that log
+		for _, a := range attrs {
+			if a.Key == logNameKey && a.Value.Kind() == slog.KindString {
+				clone.logName = a.Value.String()
+			}
+		}
+	} else {
+		// Nest the attributes under the open groups
+		group := slog.Group(h.groups[len(h.groups)-1], attrsToAny(attrs)...)
+		for i := len(h.groups) - 2; i >= 0; i-- {
+			group = slog.Group(h.groups[i], group)
+		}
+		attrs = []slog.Attr{group}
+	}
+
+	clone.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &clone
+}
+
+func (h *cloudLoggingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string(nil), h.groups...), name)
+	return &clone
+}
+
+// addPayloadAttr adds a resolved attribute to a JSON payload, expanding groups
+func addPayloadAttr(payload map[string]any, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		// Inline groups with an empty key, as slog handlers do
+		target := payload
+		if a.Key != "" {
+			existing, ok := payload[a.Key].(map[string]any)
+			if !ok {
+				existing = make(map[string]any, len(attrs))
+				payload[a.Key] = existing
+			}
+			target = existing
+		}
+		for _, ga := range attrs {
+			addPayloadAttr(target, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	payload[a.Key] = payloadValue(v)
+}
+
+// payloadValue converts a slog value into a JSON-friendly value
+func payloadValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	default:
+		return v.Any()
+	}
+}
+
+// httpRequestFromPayload converts an httpRequest payload field, in the
+// LogEntry JSON shape, to an entry HTTPRequest. It returns nil without a
+// valid requestUrl, leaving the field in the payload.
+func httpRequestFromPayload(m map[string]any) *logging.HTTPRequest {
+	rawURL, _ := m["requestUrl"].(string)
+	if rawURL == "" {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+
+	req := &http.Request{Method: stringField(m, "requestMethod"), URL: u, Header: make(http.Header)}
+	req.Proto = stringField(m, "protocol")
+	if ua := stringField(m, "userAgent"); ua != "" {
+		req.Header.Set("User-Agent", ua)
+	}
+	if ref := stringField(m, "referer"); ref != "" {
+		req.Header.Set("Referer", ref)
+	}
+
+	hr := &logging.HTTPRequest{
+		Request:                        req,
+		RequestSize:                    intField(m, "requestSize"),
+		Status:                         int(intField(m, "status")),
+		ResponseSize:                   intField(m, "responseSize"),
+		LocalIP:                        stringField(m, "serverIp"),
+		RemoteIP:                       stringField(m, "remoteIp"),
+		CacheHit:                       m["cacheHit"] == true,
+		CacheLookup:                    m["cacheLookup"] == true,
+		CacheValidatedWithOriginServer: m["cacheValidatedWithOriginServer"] == true,
+		CacheFillBytes:                 intField(m, "cacheFillBytes"),
+	}
+	if latency, err := time.ParseDuration(stringField(m, "latency")); err == nil {
+		hr.Latency = latency
+	}
+	return hr
+}
+
+// stringField returns a string field of m, or ""
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// intField returns a numeric field of m, accepting the int64-as-string JSON form
+func intField(m map[string]any, key string) int64 {
+	switch v := m[key].(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case uint64:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+func attrsToAny(attrs []slog.Attr) []any {
+	out := make([]any, len(attrs))
+	for i, a := range attrs {
+		out[i] = a
+	}
+	return out
+}
+
+// cloudLoggingSeverity maps slog levels to Cloud Logging severities
+func cloudLoggingSeverity(level slog.Level) logging.Severity {
+	switch {
+	case level >= slog.Level(LevelEmergency):
+		return logging.Emergency
+	case level >= slog.Level(LevelAlert):
+		return logging.Alert
+	case level >= slog.Level(LevelCritical):
+		return logging.Critical
+	case level >= slog.LevelError:
+		return logging.Error
+	case level >= slog.LevelWarn:
+		return logging.Warning
+	case level >= slog.Level(LevelNotice):
+		return logging.Notice
+	case level >= slog.LevelInfo:
+		return logging.Info
+	default:
+		return logging.Debug
+	}
+}
+
+// detectMonitoredResource maps the detected GCP environment onto a monitored resource
+func detectMonitoredResource(ctx context.Context, projectID string) *mrpb.MonitoredResource {
+	res, err := gcpdetector.NewDetector().Detect(ctx)
+	if err != nil || res == nil {
+		return globalResource(projectID)
+	}
+	return monitoredResourceFromAttributes(projectID, res.Attributes())
+}
+
+// monitoredResourceFromAttributes builds a monitored resource from OpenTelemetry resource attributes
+func monitoredResourceFromAttributes(projectID string, attrs []attribute.KeyValue) *mrpb.MonitoredResource {
+	values := make(map[string]string, len(attrs))
+	for _, kv := range attrs {
+		values[string(kv.Key)] = kv.Value.Emit()
+	}
+	if id := values["cloud.account.id"]; id != "" {
+		projectID = id
+	}
+	location := values["cloud.availability_zone"]
+	if location == "" {
+		location = values["cloud.region"]
+	}
+
+	switch values["cloud.platform"] {
+	case "gcp_cloud_run":
+		if values["gcp.cloud_run.job.execution"] != "" {
+			return &mrpb.MonitoredResource{Type: "cloud_run_job", Labels: map[string]string{
+				"project_id": projectID,
+				"job_name":   values["faas.name"],
+				"location":   location,
+			}}
+		}
+		return &mrpb.MonitoredResource{Type: "cloud_run_revision", Labels: map[string]string{
+			"project_id":    projectID,
+			"service_name":  values["faas.name"],
+			"revision_name": values["faas.version"],
+			"location":      location,
+		}}
+	case "gcp_cloud_functions":
+		return &mrpb.MonitoredResource{Type: "cloud_function", Labels: map[string]string{
+			"project_id":    projectID,
+			"function_name": values["faas.name"],
+			"region":        location,
+		}}
+	case "gcp_app_engine":
+		return &mrpb.MonitoredResource{Type: "gae_app", Labels: map[string]string{
+			"project_id": projectID,
+			"module_id":  values["faas.name"],
+			"version_id": values["faas.version"],
+			"zone":       location,
+		}}
+	case "gcp_kubernetes_engine":
+		return &mrpb.MonitoredResource{Type: "k8s_container", Labels: map[string]string{
+			"project_id":     projectID,
+			"location":       location,
+			"cluster_name":   values["k8s.cluster.name"],
+			"namespace_name": values["k8s.namespace.name"],
+			"pod_name":       values["k8s.pod.name"],
+			"container_name": values["k8s.container.name"],
+		}}
+	case "gcp_compute_engine":
+		return &mrpb.MonitoredResource{Type: "gce_instance", Labels: map[string]string{
+			"project_id":  projectID,
+			"instance_id": values["host.id"],
+			"zone":        location,
+		}}
+	default:
+		return globalResource(projectID)
+	}
+}
+
+func globalResource(projectID string) *mrpb.MonitoredResource {
+	return &mrpb.MonitoredResource{Type: "global", Labels: map[string]string{"project_id": projectID}}
+}
+
+// validLogName reports whether name is a valid Cloud Logging log ID
+func validLogName(name string) bool {
+	if name == "" || len(name) >= 512 {
+		return false
+	}
+	return strings.IndexFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			r == '/' || r == '_' || r == '-' || r == '.')
+	}) < 0
+}
@@ -0,0 +1,314 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+	mrpb "google.golang.org/genproto/googleapis/api/monitoredres"
+	logtypepb "google.golang.org/genproto/googleapis/logging/type"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLoggingServer is an in-process Cloud Logging API that records written entries
+type fakeLoggingServer struct {
+	loggingpb.UnimplementedLoggingServiceV2Server
+
+	mu       sync.Mutex
+	requests []*loggingpb.WriteLogEntriesRequest
+	failures int // number of calls to reject with Unavailable before accepting
+}
+
+func (s *fakeLoggingServer) WriteLogEntries(_ context.Context, req *loggingpb.WriteLogEntriesRequest) (*loggingpb.WriteLogEntriesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	s.requests = append(s.requests, req)
+	return &loggingpb.WriteLogEntriesResponse{}, nil
+}
+
+// entries returns the written entries that carry a message, keyed by log name
+func (s *fakeLoggingServer) entries() map[string][]*loggingpb.LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string][]*loggingpb.LogEntry)
+	for _, req := range s.requests {
+		for _, e := range req.Entries {
+			if e.GetJsonPayload().GetFields()["message"] == nil {
+				continue // skip the client library's diagnostic entry
+			}
+			logName := e.LogName
+			if logName == "" {
+				logName = req.LogName
+			}
+			if e.Resource == nil {
+				e.Resource = req.Resource
+			}
+			if e.Labels == nil {
+				e.Labels = req.Labels
+			}
+			out[logName] = append(out[logName], e)
+		}
+	}
+	return out
+}
+
+func startFakeLoggingServer(t *testing.T, fake *fakeLoggingServer) []option.ClientOption {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	loggingpb.RegisterLoggingServiceV2Server(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return []option.ClientOption{
+		option.WithEndpoint(lis.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	}
+}
+
+func TestCloudLoggingSink(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeLoggingServer{}
+
+	log, err := NewLogger(ctx, Config{
+		ProjectID:   "test-project",
+		ServiceName: "test-service",
+		LogName:     "app",
+		Level:       LevelInfo,
+		CloudLogging: &CloudLoggingConfig{
+			Resource:      &mrpb.MonitoredResource{Type: "global", Labels: map[string]string{"project_id": "test-project"}},
+			Labels:        map[string]string{"team": "platform"},
+			ClientOptions: startFakeLoggingServer(t, fake),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	spanCtx := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	log.WarnContext(spanCtx, "disk almost full", "percent", 91)
+	log.WithLogName("audit").InfoContext(ctx, "user deleted", "user_id", "u1")
+	log.WithLogName("access").LogHTTPRequest(spanCtx, "GET", "/orders/42", 503, 1500*time.Millisecond)
+	log.DebugContext(ctx, "filtered by level")
+
+	if err := log.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	entries := fake.entries()
+	app := entries["projects/test-project/logs/app"]
+	if len(app) != 1 {
+		t.Fatalf("Expected 1 entry in app log, got %d (%v)", len(app), entries)
+	}
+	entry := app[0]
+	if entry.Severity != logtypepb.LogSeverity_WARNING {
+		t.Errorf("Severity = %v, want WARNING", entry.Severity)
+	}
+	if got := entry.GetJsonPayload().GetFields()["message"].GetStringValue(); got != "disk almost full" {
+		t.Errorf("message = %q, want disk almost full", got)
+	}
+	if entry.Trace != "projects/test-project/traces/4bf92f3577b34da6a3ce929d0e0e4736" || entry.SpanId != "00f067aa0ba902b7" || !entry.TraceSampled {
+		t.Errorf("trace = %q span = %q sampled = %v", entry.Trace, entry.SpanId, entry.TraceSampled)
+	}
+	if entry.Resource.GetType() != "global" {
+		t.Errorf("Resource = %v, want global", entry.Resource)
+	}
+	if entry.Labels["team"] != "platform" {
+		t.Errorf("Labels = %v, want team=platform", entry.Labels)
+	}
+	if entry.SourceLocation == nil || !strings.HasSuffix(entry.SourceLocation.File, ".go") {
+		t.Errorf("SourceLocation = %v, want a Go source file", entry.SourceLocation)
+	}
+
+	if audit := entries["projects/test-project/logs/audit"]; len(audit) != 1 {
+		t.Errorf("Expected 1 entry in audit log, got %d", len(audit))
+	}
+
+	access := entries["projects/test-project/logs/access"]
+	if len(access) != 1 {
+		t.Fatalf("Expected 1 entry in access log, got %d", len(access))
+	}
+	req := access[0].HttpRequest
+	if req == nil {
+		t.Fatal("HttpRequest not set on the request log entry")
+	}
+	if req.RequestMethod != "GET" || req.RequestUrl != "/orders/42" || req.Status != 503 || req.Latency.AsDuration() != 1500*time.Millisecond {
+		t.Errorf("HttpRequest = %v", req)
+	}
+	if access[0].Trace != entry.Trace {
+		t.Errorf("access trace = %q, want %q", access[0].Trace, entry.Trace)
+	}
+
+	// Trace fields and httpRequest are entry fields, not payload keys
+	for _, e := range append(app, access...) {
+		for key := range e.GetJsonPayload().GetFields() {
+			if strings.HasPrefix(key, "logging.googleapis.com/") || key == "httpRequest" {
+				t.Errorf("payload has %q, want it only as an entry field", key)
+			}
+		}
+	}
+
+	// Logging after shutdown must not panic
+	log.InfoContext(ctx, "after shutdown")
+}
+
+func TestCloudLoggingRetry(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeLoggingServer{failures: 2}
+
+	log, err := NewLogger(ctx, Config{
+		ProjectID:   "test-project",
+		ServiceName: "test-service",
+		CloudLogging: &CloudLoggingConfig{
+			Resource:       &mrpb.MonitoredResource{Type: "global"},
+			DelayThreshold: 10 * time.Millisecond,
+			ClientOptions:  startFakeLoggingServer(t, fake),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	log.InfoContext(ctx, "eventually delivered")
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := log.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if got := len(fake.entries()["projects/test-project/logs/test-service"]); got != 1 {
+		t.Errorf("Expected entry to be delivered after retries, got %d", got)
+	}
+}
+
+func TestCloudLoggingSeverity(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  logging.Severity
+	}{
+		{LevelDebug, logging.Debug},
+		{LevelInfo, logging.Info},
+		{LevelNotice, logging.Notice},
+		{LevelWarn, logging.Warning},
+		{LevelError, logging.Error},
+		{LevelCritical, logging.Critical},
+		{LevelAlert, logging.Alert},
+		{LevelEmergency, logging.Emergency},
+	}
+	for _, tt := range tests {
+		if got := cloudLoggingSeverity(slog.Level(tt.level)); got != tt.want {
+			t.Errorf("cloudLoggingSeverity(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestCloudLoggingConfigValidate(t *testing.T) {
+	config := Config{ServiceName: "svc", LogName: "bad name!", CloudLogging: &CloudLoggingConfig{}}
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() expected error without ProjectID")
+	}
+
+	config.ProjectID = "test-project"
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() expected error for invalid log name")
+	}
+
+	config.LogName = "access/requests"
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestMonitoredResourceFromAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    []attribute.KeyValue
+		wantType string
+		wantKey  string
+		wantVal  string
+	}{
+		{
+			name: "cloud run service",
+			attrs: []attribute.KeyValue{
+				attribute.String("cloud.platform", "gcp_cloud_run"),
+				attribute.String("faas.name", "orders"),
+				attribute.String("faas.version", "orders-00042"),
+				attribute.String("cloud.region", "us-central1"),
+			},
+			wantType: "cloud_run_revision",
+			wantKey:  "revision_name",
+			wantVal:  "orders-00042",
+		},
+		{
+			name: "gke",
+			attrs: []attribute.KeyValue{
+				attribute.String("cloud.platform", "gcp_kubernetes_engine"),
+				attribute.String("k8s.cluster.name", "prod"),
+				attribute.String("cloud.availability_zone", "us-central1-a"),
+			},
+			wantType: "k8s_container",
+			wantKey:  "cluster_name",
+			wantVal:  "prod",
+		},
+		{
+			name: "compute engine",
+			attrs: []attribute.KeyValue{
+				attribute.String("cloud.platform", "gcp_compute_engine"),
+				attribute.String("host.id", "12345"),
+			},
+			wantType: "gce_instance",
+			wantKey:  "instance_id",
+			wantVal:  "12345",
+		},
+		{
+			name:     "unknown platform",
+			attrs:    nil,
+			wantType: "global",
+			wantKey:  "project_id",
+			wantVal:  "test-project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := monitoredResourceFromAttributes("test-project", tt.attrs)
+			if res.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", res.Type, tt.wantType)
+			}
+			if res.Labels[tt.wantKey] != tt.wantVal {
+				t.Errorf("Labels[%q] = %q, want %q", tt.wantKey, res.Labels[tt.wantKey], tt.wantVal)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type attrScopeKey struct{}
+
+type wideEventKey struct{}
+
+// attrScope holds attributes added to a context; scopes chain to their parent
+// so a child context sees every attribute added above it
+type attrScope struct {
+	parent *attrScope
+	attrs  []slog.Attr
+}
+
+// wideEvent collects every attribute added during a request so a single
+// canonical log line can be emitted when the request completes
+type wideEvent struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+func (e *wideEvent) add(attrs []slog.Attr) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.attrs = mergeAttrs(e.attrs, attrs)
+}
+
+func (e *wideEvent) snapshot() []slog.Attr {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]slog.Attr(nil), e.attrs...)
+}
+
+// WithAttrs returns a context carrying the given key-value pairs or slog.Attr values.
+// Context-aware log calls made with the returned context include them automatically.
+// If the context belongs to a wide event, the attributes are also recorded on it.
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	attrs := argsToAttrs(args)
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	if event, ok := ctx.Value(wideEventKey{}).(*wideEvent); ok {
+		event.add(attrs)
+	}
+
+	parent, _ := ctx.Value(attrScopeKey{}).(*attrScope)
+	return context.WithValue(ctx, attrScopeKey{}, &attrScope{parent: parent, attrs: attrs})
+}
+
+// AttrsFromContext returns the attributes stored in the context by WithAttrs.
+// When a key was added more than once, the most recent value wins.
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	scope, _ := ctx.Value(attrScopeKey{}).(*attrScope)
+	if scope == nil {
+		return nil
+	}
+
+	// Collect scopes innermost first, then merge outermost first
+	var scopes []*attrScope
+	for s := scope; s != nil; s = s.parent {
+		scopes = append(scopes, s)
+	}
+
+	var attrs []slog.Attr
+	for i := len(scopes) - 1; i >= 0; i-- {
+		attrs = mergeAttrs(attrs, scopes[i].attrs)
+	}
+	return attrs
+}
+
+// StartWideEvent returns a context that records every attribute added with
+// WithAttrs until the event is read with WideEventAttrs. It is seeded with
+// the attributes already present in the context.
+func StartWideEvent(ctx context.Context) context.Context {
+	event := &wideEvent{attrs: AttrsFromContext(ctx)}
+	return context.WithValue(ctx, wideEventKey{}, event)
+}
+
+// WideEventAttrs returns every attribute recorded on the context's wide event,
+// including those added by handlers further down the call chain
+func WideEventAttrs(ctx context.Context) []slog.Attr {
+	event, ok := ctx.Value(wideEventKey{}).(*wideEvent)
+	if !ok {
+		return nil
+	}
+	return event.snapshot()
+}
+
+// argsToAttrs converts alternating key-value pairs and slog.Attr values into
+// attributes using the same rules as slog.Logger
+func argsToAttrs(args []any) []slog.Attr {
+	if len(args) == 0 {
+		return nil
+	}
+
+	rec := slog.NewRecord(time.Time{}, 0, "", 0)
+	rec.Add(args...)
+
+	attrs := make([]slog.Attr, 0, rec.NumAttrs())
+	rec.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// mergeAttrs appends attrs to base, replacing earlier attributes with the same key
+func mergeAttrs(base, attrs []slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		replaced := false
+		for i := range base {
+			if base[i].Key == a.Key {
+				base[i] = a
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, a)
+		}
+	}
+	return base
+}
+
+// contextHandler adds attributes stored in the context to each record.
+// Attributes passed explicitly to the log call take precedence.
+type contextHandler struct {
+	handler slog.Handler
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, rec slog.Record) error {
+	attrs := AttrsFromContext(ctx)
+	if len(attrs) == 0 {
+		return h.handler.Handle(ctx, rec)
+	}
+
+	present := make(map[string]bool, rec.NumAttrs())
+	rec.Attrs(func(a slog.Attr) bool {
+		present[a.Key] = true
+		return true
+	})
+
+	rec = rec.Clone()
+	for _, a := range attrs {
+		if !present[a.Key] {
+			rec.AddAttrs(a)
+		}
+	}
+
+	return h.handler.Handle(ctx, rec)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{handler: h.handler.WithGroup(name)}
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func attrMap(attrs []slog.Attr) map[string]string {
+	m := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value.String()
+	}
+	return m
+}
+
+func TestWithAttrs(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("empty context has no attrs", func(t *testing.T) {
+		if attrs := AttrsFromContext(ctx); len(attrs) != 0 {
+			t.Errorf("AttrsFromContext() = %v, want none", attrs)
+		}
+	})
+
+	t.Run("child scopes inherit and override", func(t *testing.T) {
+		parent := WithAttrs(ctx, "request_id", "abc", "tenant", "acme")
+		child := WithAttrs(parent, "tenant", "globex", slog.String("user", "u1"))
+
+		got := attrMap(AttrsFromContext(child))
+		want := map[string]string{"request_id": "abc", "tenant": "globex", "user": "u1"}
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("AttrsFromContext()[%q] = %q, want %q", k, got[k], v)
+			}
+		}
+		if len(got) != len(want) {
+			t.Errorf("AttrsFromContext() = %v, want %v", got, want)
+		}
+
+		// Parent context is unaffected by the child
+		if attrMap(AttrsFromContext(parent))["tenant"] != "acme" {
+			t.Error("child WithAttrs modified the parent scope")
+		}
+	})
+}
+
+func TestWideEvent(t *testing.T) {
+	ctx := WithAttrs(context.Background(), "request_id", "abc")
+	ctx = StartWideEvent(ctx)
+
+	// Attributes added in a child context are recorded on the event
+	child := WithAttrs(ctx, "order_id", "42")
+	_ = WithAttrs(child, "items", 3)
+
+	got := attrMap(WideEventAttrs(ctx))
+	if got["request_id"] != "abc" || got["order_id"] != "42" || got["items"] != "3" {
+		t.Errorf("WideEventAttrs() = %v, want request_id, order_id and items", got)
+	}
+
+	if attrs := WideEventAttrs(context.Background()); attrs != nil {
+		t.Errorf("WideEventAttrs() without event = %v, want nil", attrs)
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := &Logger{logger: slog.New(&contextHandler{handler: slog.NewJSONHandler(&buf, nil)})}
+
+	ctx := WithAttrs(context.Background(), "request_id", "abc", "user", "ctx-user")
+	log.InfoContext(ctx, "processing", "user", "explicit-user")
+
+	entry := decodeEntry(t, &buf)
+	if entry["request_id"] != "abc" {
+		t.Errorf("request_id = %v, want abc", entry["request_id"])
+	}
+	if entry["user"] != "explicit-user" {
+		t.Errorf("user = %v, want explicit argument to win", entry["user"])
+	}
+}
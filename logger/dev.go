@@ -0,0 +1,362 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ANSI escape codes used by the dev handler
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiRedBg   = "\x1b[41m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+	ansiGray    = "\x1b[90m"
+)
+
+// devTimeFormat is the timestamp layout of the dev handler
+const devTimeFormat = "15:04:05.000"
+
+// Attributes the dev handler folds into the trace prefix or the request summary
+var (
+	devTraceKeys = []string{
+		"trace_id", "span_id", "trace_sampled",
+		"logging.googleapis.com/trace", "logging.googleapis.com/spanId", "logging.googleapis.com/trace_sampled",
+	}
+	devRequestKeys = []string{"http.method", "http.path", "http.status_code", "duration_ms"}
+)
+
+// DevHandlerOptions configures the developer console handler
+type DevHandlerOptions struct {
+	// Level is the minimum level written (default: Info)
+	Level slog.Leveler
+	// NoColor disables ANSI colors. Colors are also disabled when the writer
+	// is not a terminal or NO_COLOR is set.
+	NoColor bool
+}
+
+// devHandler writes compact, human-readable log lines for local development
+type devHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	level  slog.Leveler
+	color  bool
+	attrs  []slog.Attr // pre-qualified with group prefixes
+	prefix string
+}
+
+// NewDevHandler returns a handler that writes colorized, trace-aware log lines
+// such as "12:04:05.000 INFO  [4bf92f35/00f067aa] GET /users 200 12ms".
+func NewDevHandler(w io.Writer, opts *DevHandlerOptions) slog.Handler {
+	if opts == nil {
+		opts = &DevHandlerOptions{}
+	}
+	level := opts.Level
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &devHandler{
+		w:     w,
+		mu:    &sync.Mutex{},
+		level: level,
+		color: !opts.NoColor && isTerminal(w),
+	}
+}
+
+// isTerminal reports whether w is a character device and NO_COLOR is unset
+func isTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (h *devHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *devHandler) Handle(ctx context.Context, rec slog.Record) error {
+	attrs := slices.Clone(h.attrs)
+	rec.Attrs(func(a slog.Attr) bool {
+		attrs = appendQualified(attrs, h.prefix, a)
+		return true
+	})
+
+	var buf bytes.Buffer
+	if !rec.Time.IsZero() {
+		h.paint(&buf, ansiGray, rec.Time.Format(devTimeFormat))
+		buf.WriteByte(' ')
+	}
+	h.paint(&buf, devLevelColor(rec.Level), fmt.Sprintf("%-6s", devLevelName(rec.Level)))
+	buf.WriteByte(' ')
+
+	if prefix := devTracePrefix(ctx, attrs); prefix != "" {
+		h.paint(&buf, ansiDim, "["+prefix+"]")
+		buf.WriteByte(' ')
+	}
+
+	summary, hasRequest := devRequestSummary(attrs)
+	if hasRequest {
+		buf.WriteString(summary)
+		if rec.Message != "" {
+			buf.WriteByte(' ')
+			h.paint(&buf, ansiDim, rec.Message)
+		}
+	} else {
+		buf.WriteString(rec.Message)
+	}
+
+	// Multiline values (stack traces) are written as indented blocks after the line
+	var blocks []slog.Attr
+	for _, a := range attrs {
+		if slices.Contains(devTraceKeys, a.Key) || (hasRequest && (a.Key == "httpRequest" || slices.Contains(devRequestKeys, a.Key))) {
+			continue
+		}
+		s := devValue(a.Value)
+		if strings.Contains(s, "\n") {
+			blocks = append(blocks, a)
+			continue
+		}
+		buf.WriteByte(' ')
+		h.paint(&buf, ansiCyan, a.Key+"=")
+		buf.WriteString(s)
+	}
+	buf.WriteByte('\n')
+
+	for _, a := range blocks {
+		buf.WriteString("  ")
+		h.paint(&buf, ansiCyan, a.Key+":")
+		buf.WriteByte('\n')
+		for _, line := range strings.Split(strings.TrimRight(devValue(a.Value), "\n"), "\n") {
+			buf.WriteString("    ")
+			buf.WriteString(line)
+			buf.WriteByte('\n')
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *devHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		h2.attrs = appendQualified(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *devHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// paint writes s wrapped in the given color when colors are enabled
+func (h *devHandler) paint(buf *bytes.Buffer, color, s string) {
+	if !h.color {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(ansiReset)
+}
+
+// appendQualified appends a with its key prefixed by the open groups. Groups
+// other than httpRequest are flattened into dotted keys.
+func appendQualified(attrs []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() == slog.KindGroup && a.Key != "httpRequest" {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = appendQualified(attrs, groupPrefix, ga)
+		}
+		return attrs
+	}
+	a.Key = prefix + a.Key
+	return append(attrs, a)
+}
+
+// devLevelName returns the display name of a level
+func devLevelName(level slog.Level) string {
+	switch {
+	case level >= slog.Level(LevelEmergency):
+		return "EMERG"
+	case level >= slog.Level(LevelAlert):
+		return "ALERT"
+	case level >= slog.Level(LevelCritical):
+		return "CRIT"
+	case level >= slog.LevelError:
+		return "ERROR"
+	case level >= slog.LevelWarn:
+		return "WARN"
+	case level >= slog.Level(LevelNotice):
+		return "NOTICE"
+	case level >= slog.LevelInfo:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+// devLevelColor returns the ANSI color of a level
+func devLevelColor(level slog.Level) string {
+	switch {
+	case level >= slog.Level(LevelEmergency):
+		return ansiBold + ansiRedBg
+	case level >= slog.Level(LevelAlert):
+		return ansiBold + ansiRed
+	case level >= slog.Level(LevelCritical):
+		return ansiBold + ansiMagenta
+	case level >= slog.LevelError:
+		return ansiRed
+	case level >= slog.LevelWarn:
+		return ansiYellow
+	case level >= slog.Level(LevelNotice):
+		return ansiBlue
+	case level >= slog.LevelInfo:
+		return ansiGreen
+	default:
+		return ansiGray
+	}
+}
+
+// devTracePrefix returns "trace/span" shortened to 8 characters each, taken
+// from the span in ctx or the trace_id and span_id attributes
+func devTracePrefix(ctx context.Context, attrs []slog.Attr) string {
+	var traceID, spanID string
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		traceID, spanID = sc.TraceID().String(), sc.SpanID().String()
+	} else {
+		for _, a := range attrs {
+			switch a.Key {
+			case "trace_id":
+				traceID = a.Value.String()
+			case "span_id":
+				spanID = a.Value.String()
+			}
+		}
+	}
+	if traceID == "" {
+		return ""
+	}
+	prefix := shortID(traceID)
+	if spanID != "" {
+		prefix += "/" + shortID(spanID)
+	}
+	return prefix
+}
+
+// shortID truncates an ID to its first 8 characters
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// devRequestSummary renders the httpRequest attribute as "GET /path 200 12ms"
+func devRequestSummary(attrs []slog.Attr) (string, bool) {
+	var fields map[string]any
+	for _, a := range attrs {
+		if a.Key != "httpRequest" {
+			continue
+		}
+		switch a.Value.Kind() {
+		case slog.KindGroup:
+			fields = make(map[string]any)
+			for _, ga := range a.Value.Group() {
+				fields[ga.Key] = ga.Value.Any()
+			}
+		case slog.KindAny:
+			fields, _ = a.Value.Any().(map[string]any)
+		}
+	}
+	if fields == nil {
+		return "", false
+	}
+
+	parts := make([]string, 0, 4)
+	for _, key := range []string{"requestMethod", "requestUrl", "status"} {
+		if v, ok := fields[key]; ok {
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+	if latency, ok := fields["latency"].(string); ok {
+		if d, err := time.ParseDuration(latency); err == nil {
+			parts = append(parts, roundDuration(d).String())
+		}
+	}
+	return strings.Join(parts, " "), true
+}
+
+// roundDuration trims a duration to a readable precision
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
+
+// devValue formats an attribute value, quoting strings that need it
+func devValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindString:
+		s := v.String()
+		if strings.Contains(s, "\n") {
+			return s
+		}
+		if s == "" || strings.ContainsAny(s, " \t\"=") {
+			return strconv.Quote(s)
+		}
+		return s
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindGroup:
+		return fmt.Sprint(v.Group())
+	default:
+		return fmt.Sprint(v.Any())
+	}
+}
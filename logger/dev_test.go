@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDevHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("plain line without color", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(NewDevHandler(&buf, nil))
+		log.Info("user created", "user_id", 42, "name", "Jane Doe")
+
+		got := buf.String()
+		if strings.Contains(got, "\x1b[") {
+			t.Errorf("non-terminal output contains ANSI codes: %q", got)
+		}
+		for _, want := range []string{"INFO ", "user created", "user_id=42", `name="Jane Doe"`} {
+			if !strings.Contains(got, want) {
+				t.Errorf("output %q missing %q", got, want)
+			}
+		}
+	})
+
+	t.Run("level filtering", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(NewDevHandler(&buf, &DevHandlerOptions{Level: slog.LevelWarn}))
+		log.Info("hidden")
+		log.Warn("shown")
+		if got := buf.String(); strings.Contains(got, "hidden") || !strings.Contains(got, "WARN   shown") {
+			t.Errorf("output = %q", got)
+		}
+	})
+
+	t.Run("color", func(t *testing.T) {
+		var buf bytes.Buffer
+		h := NewDevHandler(&buf, nil).(*devHandler)
+		h.color = true
+		slog.New(h).Error("boom")
+		if got := buf.String(); !strings.Contains(got, ansiRed+"ERROR "+ansiReset) {
+			t.Errorf("output %q missing red ERROR", got)
+		}
+	})
+
+	t.Run("trace prefix from span context", func(t *testing.T) {
+		var buf bytes.Buffer
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		spanCtx := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+
+		slog.New(NewDevHandler(&buf, nil)).InfoContext(spanCtx, "traced", "trace_id", traceID.String())
+		got := buf.String()
+		if !strings.Contains(got, "[4bf92f35/00f067aa] traced") {
+			t.Errorf("output %q missing trace prefix", got)
+		}
+		if strings.Contains(got, "trace_id=") {
+			t.Errorf("output %q repeats trace_id attribute", got)
+		}
+	})
+
+	t.Run("http request summary", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := &Logger{logger: slog.New(NewDevHandler(&buf, nil)), config: Config{}}
+		log.LogHTTPRequest(ctx, "GET", "/users", 200, 12*time.Millisecond, "request_id", "abc")
+
+		got := buf.String()
+		if !strings.Contains(got, "GET /users 200 12ms") {
+			t.Errorf("output %q missing request summary", got)
+		}
+		if strings.Contains(got, "http.method=") || strings.Contains(got, "httpRequest=") {
+			t.Errorf("output %q repeats request fields", got)
+		}
+		if !strings.Contains(got, "request_id=abc") {
+			t.Errorf("output %q missing extra attributes", got)
+		}
+	})
+
+	t.Run("stack traces are indented", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := &Logger{logger: slog.New(NewDevHandler(&buf, nil)), config: Config{EnableErrorReporting: true}}
+		log.LogError(ctx, errors.New("db down"), "query failed")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) < 3 {
+			t.Fatalf("output = %q, want indented stack block", buf.String())
+		}
+		if strings.Contains(lines[0], stackTraceKey) {
+			t.Errorf("first line %q contains the stack trace", lines[0])
+		}
+		if lines[1] != "  "+stackTraceKey+":" {
+			t.Errorf("block header = %q", lines[1])
+		}
+		for _, line := range lines[2:] {
+			if !strings.HasPrefix(line, "    ") {
+				t.Errorf("stack line %q is not indented", line)
+			}
+		}
+	})
+
+	t.Run("groups are flattened", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(NewDevHandler(&buf, nil)).WithGroup("db").With("table", "users")
+		log.Info("query", slog.Group("stats", "rows", 3))
+		if got := buf.String(); !strings.Contains(got, "db.table=users db.stats.rows=3") {
+			t.Errorf("output = %q", got)
+		}
+	})
+}
+
+func TestDevLevelName(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  string
+	}{
+		{slog.LevelDebug, "DEBUG"},
+		{slog.LevelInfo, "INFO"},
+		{slog.Level(LevelNotice), "NOTICE"},
+		{slog.LevelWarn, "WARN"},
+		{slog.LevelError, "ERROR"},
+		{slog.Level(LevelCritical), "CRIT"},
+		{slog.Level(LevelAlert), "ALERT"},
+		{slog.Level(LevelEmergency), "EMERG"},
+	}
+	for _, tt := range tests {
+		if got := devLevelName(tt.level); got != tt.want {
+			t.Errorf("devLevelName(%v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/sirhco/go-gcp-middleware/internal/stack"
+)
+
+// reportedErrorEventType marks an entry as an error for Cloud Error Reporting
+// when it carries no stack trace of its own
+const reportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// stackTraceKey is the field Cloud Error Reporting inspects for a stack trace
+const stackTraceKey = "stack_trace"
+
+// errorReportingHandler decorates error-level records so Cloud Error Reporting
+// can group them: serviceContext is always added and @type is added when the
+// record does not carry a stack_trace field
+type errorReportingHandler struct {
+	handler slog.Handler
+	service string
+	version string
+}
+
+func newErrorReportingHandler(handler slog.Handler, service, version string) *errorReportingHandler {
+	return &errorReportingHandler{
+		handler: handler,
+		service: service,
+		version: version,
+	}
+}
+
+func (h *errorReportingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *errorReportingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if rec.Level < slog.LevelError {
+		return h.handler.Handle(ctx, rec)
+	}
+
+	hasStack := false
+	rec.Attrs(func(a slog.Attr) bool {
+		if a.Key == stackTraceKey {
+			hasStack = true
+			return false
+		}
+		return true
+	})
+
+	rec = rec.Clone()
+	rec.AddAttrs(slog.Group("serviceContext",
+		slog.String("service", h.service),
+		slog.String("version", h.version),
+	))
+	if !hasStack {
+		rec.AddAttrs(slog.String("@type", reportedErrorEventType))
+	}
+
+	return h.handler.Handle(ctx, rec)
+}
+
+func (h *errorReportingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &errorReportingHandler{handler: h.handler.WithAttrs(attrs), service: h.service, version: h.version}
+}
+
+func (h *errorReportingHandler) WithGroup(name string) slog.Handler {
+	return &errorReportingHandler{handler: h.handler.WithGroup(name), service: h.service, version: h.version}
+}
+
+// formatStack renders the caller's stack in the runtime.Stack layout that
+// Cloud Error Reporting parses for Go, headed by the error message.
+// skip is the number of frames to skip, with 0 identifying the caller of formatStack.
+func formatStack(msg string, skip int) string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+	return stack.Format(msg, pcs[:n])
+}
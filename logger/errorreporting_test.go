@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrorReportingHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("error without stack gets @type", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ServiceName: "svc", ServiceVersion: "1.2.3", EnableErrorReporting: true}, &buf)
+
+		log.ErrorContext(ctx, "something broke")
+
+		entry := decodeEntry(t, &buf)
+		if entry["@type"] != reportedErrorEventType {
+			t.Errorf("@type = %v, want %v", entry["@type"], reportedErrorEventType)
+		}
+		sc, ok := entry["serviceContext"].(map[string]any)
+		if !ok {
+			t.Fatalf("serviceContext missing: %v", entry)
+		}
+		if sc["service"] != "svc" || sc["version"] != "1.2.3" {
+			t.Errorf("serviceContext = %v, want service=svc version=1.2.3", sc)
+		}
+	})
+
+	t.Run("LogError includes stack trace", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ServiceName: "svc", EnableErrorReporting: true}, &buf)
+
+		log.LogError(ctx, errors.New("boom"), "operation failed")
+
+		entry := decodeEntry(t, &buf)
+		if _, ok := entry["@type"]; ok {
+			t.Error("@type should be omitted when a stack trace is present")
+		}
+		stack, _ := entry[stackTraceKey].(string)
+		if !strings.HasPrefix(stack, "boom\n\ngoroutine 1 [running]:\n") {
+			t.Errorf("stack_trace has unexpected header: %q", stack)
+		}
+		if !strings.Contains(stack, "TestErrorReportingHandler") {
+			t.Errorf("stack_trace does not contain caller frame: %q", stack)
+		}
+		if strings.Contains(stack, "logger.formatStack") {
+			t.Errorf("stack_trace contains logger internals: %q", stack)
+		}
+	})
+
+	t.Run("info logs are untouched", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ServiceName: "svc", EnableErrorReporting: true}, &buf)
+
+		log.InfoContext(ctx, "all good")
+
+		entry := decodeEntry(t, &buf)
+		if _, ok := entry["serviceContext"]; ok {
+			t.Error("serviceContext should only be added to error logs")
+		}
+	})
+
+	t.Run("disabled adds nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{ServiceName: "svc"}, &buf)
+
+		log.LogError(ctx, errors.New("boom"), "operation failed")
+
+		entry := decodeEntry(t, &buf)
+		if _, ok := entry[stackTraceKey]; ok {
+			t.Error("stack_trace should not be added when error reporting is disabled")
+		}
+		if _, ok := entry["serviceContext"]; ok {
+			t.Error("serviceContext should not be added when error reporting is disabled")
+		}
+	})
+}
+
+func TestLogPanicErrorReporting(t *testing.T) {
+	var buf bytes.Buffer
+	log := newTestLogger(t, Config{ServiceName: "svc", EnableErrorReporting: true}, &buf)
+
+	func() {
+		defer func() { _ = recover() }()
+		defer log.LogPanic(context.Background())
+		panic("kaboom")
+	}()
+
+	entry := decodeEntry(t, &buf)
+	stack, _ := entry[stackTraceKey].(string)
+	if !strings.HasPrefix(stack, "panic: kaboom\n\ngoroutine ") {
+		t.Errorf("stack_trace has unexpected header: %q", stack)
+	}
+}
+
+func TestLogRecoveredPanic(t *testing.T) {
+	var buf bytes.Buffer
+	log := newTestLogger(t, Config{ServiceName: "svc", EnableErrorReporting: true}, &buf)
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.LogRecoveredPanic(context.Background(), r)
+			}
+		}()
+		panic("kaboom")
+	}()
+
+	entry := decodeEntry(t, &buf)
+	if entry["panic.value"] != "kaboom" {
+		t.Errorf("panic.value = %v, want kaboom", entry["panic.value"])
+	}
+	stack, _ := entry[stackTraceKey].(string)
+	if !strings.HasPrefix(stack, "panic: kaboom\n\ngoroutine ") {
+		t.Errorf("stack_trace has unexpected header: %q", stack)
+	}
+}
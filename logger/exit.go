@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultExitTimeout bounds how long Fatal waits for exit hooks
+const defaultExitTimeout = 5 * time.Second
+
+// ExitHook runs before Fatal exits the process, e.g. to flush spans
+type ExitHook func(ctx context.Context) error
+
+var (
+	exitMu      sync.Mutex
+	exitHooks   []*ExitHook
+	exitTimeout = defaultExitTimeout
+
+	// osExit is replaced in tests
+	osExit = os.Exit
+)
+
+// RegisterExitHook registers hook to run when Fatal is called. Hooks run in
+// reverse registration order, like deferred calls. The returned function
+// unregisters the hook.
+func RegisterExitHook(hook ExitHook) (unregister func()) {
+	h := &hook
+	exitMu.Lock()
+	exitHooks = append(exitHooks, h)
+	exitMu.Unlock()
+
+	return func() {
+		exitMu.Lock()
+		defer exitMu.Unlock()
+		exitHooks = slices.DeleteFunc(exitHooks, func(e *ExitHook) bool { return e == h })
+	}
+}
+
+// SetExitTimeout sets how long Fatal waits for exit hooks (default: 5s)
+func SetExitTimeout(d time.Duration) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitTimeout = d
+}
+
+// runExitHooks runs the registered hooks until they finish or ctx is done.
+// A hook that ignores ctx cannot hold up the exit.
+func runExitHooks(ctx context.Context) {
+	exitMu.Lock()
+	hooks := slices.Clone(exitHooks)
+	exitMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, h := range slices.Backward(hooks) {
+			if err := runExitHook(ctx, *h); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: exit hook failed: %v\n", err)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		fmt.Fprintf(os.Stderr, "logger: exit hooks did not finish: %v\n", ctx.Err())
+	}
+}
+
+// runExitHook runs a single hook, turning a panic into an error
+func runExitHook(ctx context.Context, hook ExitHook) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return hook(ctx)
+}
+
+// exit runs the exit hooks, drains l and exits with status 1
+func (l *Logger) exit() {
+	exitMu.Lock()
+	timeout := exitTimeout
+	exitMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	runExitHooks(ctx)
+	_ = l.Shutdown(ctx)
+	osExit(1)
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stubExit replaces osExit for the duration of the test and returns the recorded exit codes
+func stubExit(t *testing.T) *[]int {
+	t.Helper()
+	var codes []int
+	orig := osExit
+	osExit = func(code int) { codes = append(codes, code) }
+	t.Cleanup(func() { osExit = orig })
+	return &codes
+}
+
+func TestFatalRunsExitHooks(t *testing.T) {
+	codes := stubExit(t)
+
+	var order []string
+	unregisterA := RegisterExitHook(func(context.Context) error {
+		order = append(order, "a")
+		return nil
+	})
+	defer unregisterA()
+	unregisterB := RegisterExitHook(func(context.Context) error {
+		order = append(order, "b")
+		return errors.New("flush failed")
+	})
+	defer unregisterB()
+	unregisterC := RegisterExitHook(func(context.Context) error {
+		panic("hook bug")
+	})
+	defer unregisterC()
+
+	var buf bytes.Buffer
+	log := newTestLogger(t, Config{ServiceName: "svc"}, &buf)
+	log.Fatal("cannot start", "port", 8080)
+
+	if !slices.Equal(*codes, []int{1}) {
+		t.Errorf("exit codes = %v, want [1]", *codes)
+	}
+	if !slices.Equal(order, []string{"b", "a"}) {
+		t.Errorf("hook order = %v, want [b a]", order)
+	}
+	entry := decodeEntry(t, &buf)
+	if entry["level"] != "ERROR+4" || entry["msg"] != "cannot start" {
+		t.Errorf("entry = %v, want critical fatal message", entry)
+	}
+}
+
+func TestFatalHookTimeout(t *testing.T) {
+	codes := stubExit(t)
+	SetExitTimeout(50 * time.Millisecond)
+	defer SetExitTimeout(defaultExitTimeout)
+
+	block := make(chan struct{})
+	defer close(block)
+	unregister := RegisterExitHook(func(context.Context) error {
+		<-block // ignores ctx
+		return nil
+	})
+	defer unregister()
+
+	var buf bytes.Buffer
+	log := newTestLogger(t, Config{ServiceName: "svc"}, &buf)
+
+	start := time.Now()
+	log.FatalContext(context.Background(), "stuck")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Fatal took %v, want bounded by the exit timeout", elapsed)
+	}
+	if len(*codes) != 1 {
+		t.Errorf("exit codes = %v, want one exit", *codes)
+	}
+}
+
+func TestRegisterExitHookUnregister(t *testing.T) {
+	stubExit(t)
+
+	called := false
+	unregister := RegisterExitHook(func(context.Context) error {
+		called = true
+		return nil
+	})
+	unregister()
+
+	var buf bytes.Buffer
+	newTestLogger(t, Config{ServiceName: "svc"}, &buf).Fatal("bye")
+	if called {
+		t.Error("unregistered hook was called")
+	}
+}
+
+func TestFatalDrainsAsyncQueue(t *testing.T) {
+	stubExit(t)
+
+	rec := &recordingHandler{}
+	log, err := NewLogger(context.Background(), Config{
+		ServiceName: "svc",
+		Handlers:    []slog.Handler{rec},
+		Async:       &AsyncConfig{QueueSize: 16},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	log.Info("before")
+	log.Fatal("fatal")
+
+	if got := rec.messages(); !slices.Equal(got, []string{"before", "fatal"}) {
+		t.Errorf("written = %v, want both records drained before exit", got)
+	}
+}
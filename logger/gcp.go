@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/chainguard-dev/clog/gcp"
+)
+
+// gcpHandler writes JSON in the structured logging format the Cloud Logging
+// agent parses from stderr. It follows clog/gcp, which only names CRITICAL,
+// and maps every Cloud Logging severity.
+type gcpHandler struct {
+	handler slog.Handler
+}
+
+// newGCPHandler returns a handler writing GCP structured logs to w
+func newGCPHandler(w io.Writer) *gcpHandler {
+	return &gcpHandler{handler: slog.NewJSONHandler(w, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     minLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.MessageKey:
+				a.Key = "message"
+			case slog.SourceKey:
+				a.Key = "logging.googleapis.com/sourceLocation"
+			case slog.LevelKey:
+				a.Key = "severity"
+				if level, ok := a.Value.Any().(slog.Level); ok {
+					a.Value = slog.StringValue(gcpSeverity(level))
+				}
+			}
+			return a
+		},
+	})}
+}
+
+func (h *gcpHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *gcpHandler) Handle(ctx context.Context, rec slog.Record) error {
+	// Correlate with the request log, see https://cloud.google.com/trace/docs/trace-log-integration
+	if trace := gcp.TraceFromContext(ctx); trace != "" {
+		rec = rec.Clone()
+		rec.Add("logging.googleapis.com/trace", slog.StringValue(trace))
+	}
+	return h.handler.Handle(ctx, rec)
+}
+
+func (h *gcpHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &gcpHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *gcpHandler) WithGroup(name string) slog.Handler {
+	return &gcpHandler{handler: h.handler.WithGroup(name)}
+}
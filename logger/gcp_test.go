@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/chainguard-dev/clog/gcp"
+)
+
+func TestGCPHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(newGCPHandler(&buf))
+
+	ctx := gcp.WithTrace(context.Background(), "projects/p/traces/abc")
+	log.Log(ctx, slog.Level(LevelAlert), "disk full", "volume", "data")
+
+	entry := decodeEntry(t, &buf)
+	if entry["severity"] != "ALERT" {
+		t.Errorf("severity = %v, want ALERT", entry["severity"])
+	}
+	if entry["message"] != "disk full" {
+		t.Errorf("message = %v", entry["message"])
+	}
+	if entry["logging.googleapis.com/trace"] != "projects/p/traces/abc" {
+		t.Errorf("trace = %v", entry["logging.googleapis.com/trace"])
+	}
+	if _, ok := entry["logging.googleapis.com/sourceLocation"]; !ok {
+		t.Error("sourceLocation missing")
+	}
+	if entry["volume"] != "data" {
+		t.Errorf("volume = %v", entry["volume"])
+	}
+}
+
+func TestGCPHandlerSeverities(t *testing.T) {
+	for _, n := range levelNames {
+		var buf bytes.Buffer
+		slog.New(newGCPHandler(&buf)).Log(context.Background(), slog.Level(n.level), "msg")
+		if got, want := decodeEntry(t, &buf)["severity"], gcpSeverity(slog.Level(n.level)); got != want {
+			t.Errorf("severity for %v = %v, want %v", n.level, got, want)
+		}
+	}
+}
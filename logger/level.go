@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// levelNames maps each named level to its display name
+var levelNames = []struct {
+	level Level
+	name  string
+}{
+	{LevelDebug, "DEBUG"},
+	{LevelInfo, "INFO"},
+	{LevelNotice, "NOTICE"},
+	{LevelWarn, "WARN"},
+	{LevelError, "ERROR"},
+	{LevelCritical, "CRITICAL"},
+	{LevelAlert, "ALERT"},
+	{LevelEmergency, "EMERGENCY"},
+}
+
+// String returns the level name, with an offset for levels between names
+// the way slog does, e.g. "NOTICE" or "ERROR+1"
+func (l Level) String() string {
+	for i := len(levelNames) - 1; i >= 0; i-- {
+		n := levelNames[i]
+		if l == n.level {
+			return n.name
+		}
+		if l > n.level {
+			return fmt.Sprintf("%s+%d", n.name, int(l-n.level))
+		}
+	}
+	return fmt.Sprintf("DEBUG%d", int(l-LevelDebug))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel
+func (l *Level) UnmarshalText(data []byte) error {
+	level, err := ParseLevel(string(data))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ParseLevel parses a level name, case-insensitively. It accepts the GCP
+// severities (DEBUG, INFO, NOTICE, WARNING, ERROR, CRITICAL, ALERT, EMERGENCY),
+// the slog names with optional offsets (WARN, INFO+2), and plain integers.
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	switch name {
+	case "WARNING":
+		return LevelWarn, nil
+	case "CRIT":
+		return LevelCritical, nil
+	case "EMERG":
+		return LevelEmergency, nil
+	}
+
+	if n, err := strconv.Atoi(name); err == nil {
+		return Level(n), nil
+	}
+
+	base, offset := name, 0
+	if i := strings.IndexAny(name, "+-"); i > 0 {
+		n, err := strconv.Atoi(name[i:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid log level %q: bad offset", s)
+		}
+		base, offset = name[:i], n
+	}
+	for _, n := range levelNames {
+		if n.name == base {
+			return n.level + Level(offset), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid log level %q", s)
+}
+
+// gcpSeverity returns the Cloud Logging severity name for a level
+func gcpSeverity(level slog.Level) string {
+	switch {
+	case level >= slog.Level(LevelEmergency):
+		return "EMERGENCY"
+	case level >= slog.Level(LevelAlert):
+		return "ALERT"
+	case level >= slog.Level(LevelCritical):
+		return "CRITICAL"
+	case level >= slog.LevelError:
+		return "ERROR"
+	case level >= slog.LevelWarn:
+		return "WARNING"
+	case level >= slog.Level(LevelNotice):
+		return "NOTICE"
+	case level >= slog.LevelInfo:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+// replaceLevelName renders levels by name in the console handlers
+func replaceLevelName(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.LevelKey {
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(Level(level).String())
+		}
+	}
+	return a
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestLevelString(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{LevelDebug, "DEBUG"},
+		{LevelDebug - 2, "DEBUG-2"},
+		{LevelInfo, "INFO"},
+		{LevelNotice, "NOTICE"},
+		{LevelNotice + 1, "NOTICE+1"},
+		{LevelWarn, "WARN"},
+		{LevelError, "ERROR"},
+		{LevelCritical, "CRITICAL"},
+		{LevelAlert, "ALERT"},
+		{LevelEmergency, "EMERGENCY"},
+		{LevelEmergency + 4, "EMERGENCY+4"},
+	}
+	for _, tt := range tests {
+		if got := tt.level.String(); got != tt.want {
+			t.Errorf("Level(%d).String() = %q, want %q", int(tt.level), got, tt.want)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Level
+		wantErr bool
+	}{
+		{in: "debug", want: LevelDebug},
+		{in: "INFO", want: LevelInfo},
+		{in: " notice ", want: LevelNotice},
+		{in: "warn", want: LevelWarn},
+		{in: "WARNING", want: LevelWarn},
+		{in: "error", want: LevelError},
+		{in: "critical", want: LevelCritical},
+		{in: "crit", want: LevelCritical},
+		{in: "alert", want: LevelAlert},
+		{in: "emergency", want: LevelEmergency},
+		{in: "emerg", want: LevelEmergency},
+		{in: "INFO+2", want: LevelNotice},
+		{in: "debug-1", want: LevelDebug - 1},
+		{in: "8", want: LevelError},
+		{in: "-4", want: LevelDebug},
+		{in: "verbose", wantErr: true},
+		{in: "INFO+x", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLevelTextRoundTrip(t *testing.T) {
+	for _, n := range levelNames {
+		text, err := n.level.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText() error = %v", err)
+		}
+		var got Level
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) error = %v", text, err)
+		}
+		if got != n.level {
+			t.Errorf("round trip of %v = %v", n.level, got)
+		}
+	}
+}
+
+func TestGCPSeverity(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{LevelDebug, "DEBUG"},
+		{LevelInfo, "INFO"},
+		{LevelNotice, "NOTICE"},
+		{LevelWarn, "WARNING"},
+		{LevelError, "ERROR"},
+		{LevelError + 1, "ERROR"},
+		{LevelCritical, "CRITICAL"},
+		{LevelAlert, "ALERT"},
+		{LevelEmergency, "EMERGENCY"},
+	}
+	for _, tt := range tests {
+		if got := gcpSeverity(slog.Level(tt.level)); got != tt.want {
+			t.Errorf("gcpSeverity(%v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestReplaceLevelName(t *testing.T) {
+	a := replaceLevelName(nil, slog.Any(slog.LevelKey, slog.Level(LevelNotice)))
+	if a.Value.String() != "NOTICE" {
+		t.Errorf("level = %q, want NOTICE", a.Value.String())
+	}
+	a = replaceLevelName([]string{"g"}, slog.Any(slog.LevelKey, slog.Level(LevelNotice)))
+	if _, ok := a.Value.Any().(slog.Level); !ok {
+		t.Error("grouped level attribute was replaced")
+	}
+}
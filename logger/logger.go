@@ -2,48 +2,129 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"log/slog"
+	"math"
 	"os"
 	"runtime"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/chainguard-dev/clog/gcp"
+	"github.com/sirhco/go-gcp-middleware/errs"
+	"github.com/sirhco/go-gcp-middleware/internal/attrconv"
+	"github.com/sirhco/go-gcp-middleware/internal/stack"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 var (
-	globalLogger *Logger
-	once         sync.Once
+	// globalLogger is swapped atomically so request goroutines can read it
+	// while tests reset it; globalMu serializes InitGlobal
+	globalLogger atomic.Pointer[Logger]
+	globalMu     sync.Mutex
+
+	// Process defaults restored by ResetGlobal
+	defaultSlog     = slog.Default()
+	defaultLogOut   = log.Writer()
+	defaultLogFlags = log.Flags()
 )
 
+// defaultCloseTimeout bounds how long Close waits for buffered records to drain
+const defaultCloseTimeout = 5 * time.Second
+
+// minLevel lets every record through a handler that only accepts a fixed level
+const minLevel = slog.Level(math.MinInt)
+
 // Level represents logging levels
 type Level slog.Level
 
-// Log levels
+// Log levels, covering every Cloud Logging severity
+const (
+	LevelDebug     = Level(slog.LevelDebug)
+	LevelInfo      = Level(slog.LevelInfo)
+	LevelNotice    = Level(2)
+	LevelWarn      = Level(slog.LevelWarn)
+	LevelError     = Level(slog.LevelError)
+	LevelCritical  = Level(gcp.LevelCritical)
+	LevelAlert     = Level(16)
+	LevelEmergency = Level(20)
+)
+
+// Level implements slog.Leveler so a Level can be used as a sink level
+func (l Level) Level() slog.Level {
+	return slog.Level(l)
+}
+
+// Format selects the output encoding of a sink
+type Format string
+
+// Output formats
 const (
-	LevelDebug    = Level(slog.LevelDebug)
-	LevelInfo     = Level(slog.LevelInfo)
-	LevelWarn     = Level(slog.LevelWarn)
-	LevelError    = Level(slog.LevelError)
-	LevelCritical = Level(gcp.LevelCritical)
+	FormatJSON Format = "json"
+	FormatText Format = "text"
+	FormatDev  Format = "dev" // Colorized, human-readable console output
 )
 
+// SinkOptions overrides the level and format of a single built-in sink
+type SinkOptions struct {
+	// Level is the minimum level written to the sink. Nil follows Config.Level and SetLevel.
+	Level slog.Leveler
+	// Format is the sink's output format. Empty keeps the sink default.
+	Format Format
+}
+
 // Config holds logger configuration
 type Config struct {
 	ProjectID      string
 	ServiceName    string
 	ServiceVersion string
-	LogName        string // LogName added as a field in logs for filtering/categorization. NOTE: When using stderr output, GCP automatically sets the actual logName to "projects/{PROJECT_ID}/logs/stderr". To use custom log names in GCP, you must use the Cloud Logging API client library.
+	LogName        string // LogName added as a field in logs for filtering/categorization. NOTE: When using stderr output, GCP automatically sets the actual logName to "projects/{PROJECT_ID}/logs/stderr". Set CloudLogging to write entries under the real log name.
 	EnableConsole  bool   // Enable console output to stdout
 	EnableGCP      bool   // Enable GCP structured logging to stderr
 	Level          Level
-	Pretty         bool // Pretty-print console logs (text format vs JSON)
+	Pretty         bool // Pretty-print console logs with the dev handler (vs JSON)
+
+	// Per-sink overrides, e.g. console at debug while GCP stays at info
+	Console SinkOptions
+	GCP     SinkOptions
+
+	// Handlers are additional sinks. Each filters records with its own Enabled.
+	Handlers []slog.Handler
+
+	// EnableErrorReporting adds serviceContext and a Go stack trace to error logs
+	// so Cloud Error Reporting groups them automatically. Errors from errs.New
+	// or errs.Wrap carry their captured stack_trace even when this is false.
+	EnableErrorReporting bool
+
+	// Redact scrubs sensitive keys and values (credentials, emails, card numbers)
+	// before they are written. Nil disables redaction.
+	Redact *RedactConfig
+
+	// RateLimit suppresses floods of identical log lines. Nil disables rate limiting.
+	RateLimit *RateLimitConfig
+
+	// Async writes records from a background goroutine through a bounded queue.
+	// Nil writes synchronously.
+	Async *AsyncConfig
+
+	// CloudLogging writes entries through the Cloud Logging API instead of
+	// stderr, keeping real log names and a monitored resource. Nil disables it.
+	CloudLogging *CloudLoggingConfig
+
+	// SpanEvents mirrors records onto the active span as "log" events
+	// without the *WithSpan methods. Nil disables it.
+	SpanEvents *SpanEventsConfig
+
+	// Audit configures the audit log written by Logger.Audit. Nil uses the
+	// "audit" log name without a hash chain.
+	Audit *AuditConfig
 }
 
 // SetDefaults sets reasonable defaults for the config
@@ -64,14 +145,42 @@ func (c *Config) Validate() error {
 	if c.EnableGCP && c.ProjectID == "" {
 		return fmt.Errorf("ProjectID is required when GCP logging is enabled")
 	}
+	if c.CloudLogging != nil {
+		if c.ProjectID == "" {
+			return fmt.Errorf("ProjectID is required when Cloud Logging API is enabled")
+		}
+		if !validLogName(c.LogName) {
+			return fmt.Errorf("LogName %q is not a valid Cloud Logging log ID", c.LogName)
+		}
+		if c.Audit != nil && c.Audit.LogName != "" && !validLogName(c.Audit.LogName) {
+			return fmt.Errorf("Audit.LogName %q is not a valid Cloud Logging log ID", c.Audit.LogName)
+		}
+	}
+	switch c.Console.Format {
+	case "", FormatJSON, FormatText, FormatDev:
+	default:
+		return fmt.Errorf("unsupported console format %q", c.Console.Format)
+	}
+	if c.GCP.Format != "" && c.GCP.Format != FormatJSON {
+		return fmt.Errorf("GCP sink only supports the %q format", FormatJSON)
+	}
 	return nil
 }
 
 // Logger wraps slog with GCP structured logging support via clog/gcp
 type Logger struct {
-	logger *slog.Logger
-	config Config
-	mu     sync.RWMutex
+	logger   *slog.Logger
+	config   Config
+	pipeline *pipeline
+	mu       sync.RWMutex
+}
+
+// pipeline holds handler state shared by a Logger and the loggers derived from it
+type pipeline struct {
+	level *slog.LevelVar
+	async *asyncQueue
+	cloud *cloudLoggingSink
+	audit *auditLog
 }
 
 // NewLogger creates a new logger instance with the provided config
@@ -81,38 +190,108 @@ func NewLogger(ctx context.Context, config Config) (*Logger, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	handler := createHandler(config)
+	p := &pipeline{level: new(slog.LevelVar)}
+	p.level.Set(slog.Level(config.Level))
+
+	if config.CloudLogging != nil {
+		sink, err := newCloudLoggingSink(ctx, config.ProjectID, *config.CloudLogging)
+		if err != nil {
+			return nil, err
+		}
+		p.cloud = sink
+	}
+
+	handler := createHandler(config, p)
+	p.audit = newAuditLog(config, p)
 
 	logger := &Logger{
-		logger: slog.New(handler),
-		config: config,
+		logger:   slog.New(handler),
+		config:   config,
+		pipeline: p,
 	}
 
 	return logger, nil
 }
 
 // createHandler creates the appropriate slog handler based on config
-func createHandler(config Config) slog.Handler {
+func createHandler(config Config, p *pipeline) slog.Handler {
+	handler := createSinkHandler(config, p)
+
+	// Redaction runs before any sink sees the record
+	if config.Redact != nil {
+		handler = newRedactHandler(handler, NewRedactor(*config.Redact))
+	}
+
+	// Error Reporting decoration applies to every sink
+	if config.EnableErrorReporting {
+		handler = newErrorReportingHandler(handler, config.ServiceName, config.ServiceVersion)
+	}
+
+	// Attributes stored in the context with WithAttrs are added to every record
+	handler = &contextHandler{handler: handler}
+
+	// Everything below runs on the background worker when async is enabled
+	if config.Async != nil {
+		async := newAsyncHandler(handler, *config.Async)
+		p.async = async.queue
+		handler = async
+	}
+
+	// Span events are added synchronously, while the span is still open
+	if config.SpanEvents != nil {
+		var redactor *Redactor
+		if config.Redact != nil {
+			redactor = NewRedactor(*config.Redact)
+		}
+		handler = newSpanEventsHandler(handler, *config.SpanEvents, redactor)
+	}
+
+	// Flood protection drops repeated lines before any other work is done
+	if config.RateLimit != nil {
+		handler = newRateLimitHandler(handler, *config.RateLimit)
+	}
+
+	return handler
+}
+
+// createSinkHandler creates the output handlers (console, GCP, Cloud Logging API, custom) based on config
+func createSinkHandler(config Config, p *pipeline) slog.Handler {
 	var handlers []slog.Handler
 
 	// Console handler (stdout)
 	if config.EnableConsole {
-		opts := &slog.HandlerOptions{Level: slog.Level(config.Level)}
-		if config.Pretty {
+		opts := &slog.HandlerOptions{Level: sinkLevel(config.Console, p.level), ReplaceAttr: replaceLevelName}
+		format := config.Console.Format
+		if format == "" && config.Pretty {
+			format = FormatDev
+		}
+		switch format {
+		case FormatDev:
+			handlers = append(handlers, NewDevHandler(os.Stdout, &DevHandlerOptions{Level: opts.Level}))
+		case FormatText:
 			handlers = append(handlers, slog.NewTextHandler(os.Stdout, opts))
-		} else {
+		default:
 			handlers = append(handlers, slog.NewJSONHandler(os.Stdout, opts))
 		}
 	}
 
 	// GCP handler (stderr) - clog/gcp automatically formats for GCP Cloud Logging
 	if config.EnableGCP {
-		handlers = append(handlers, gcp.NewHandler(slog.Level(config.Level)))
+		// Gate with a dynamic level so SetLevel applies
+		handlers = append(handlers, &levelHandler{handler: newGCPHandler(os.Stderr), level: sinkLevel(config.GCP, p.level)})
+	}
+
+	// Cloud Logging API handler - entries keep their real log name
+	if p.cloud != nil {
+		handlers = append(handlers, newCloudLoggingHandler(p.cloud, config.LogName, sinkLevel(config.GCP, p.level)))
 	}
 
+	// User-registered sinks
+	handlers = append(handlers, config.Handlers...)
+
 	// If no handlers enabled, use a discard handler
 	if len(handlers) == 0 {
-		return slog.NewTextHandler(io.Discard, nil)
+		return slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: p.level})
 	}
 
 	// If only one handler, return it directly
@@ -124,7 +303,38 @@ func createHandler(config Config) slog.Handler {
 	return &multiHandler{handlers: handlers}
 }
 
-// multiHandler writes to multiple handlers
+// sinkLevel returns the sink's own level, or the shared level when none is set
+func sinkLevel(opts SinkOptions, shared slog.Leveler) slog.Leveler {
+	if opts.Level != nil {
+		return opts.Level
+	}
+	return shared
+}
+
+// levelHandler filters records below a dynamic level
+type levelHandler struct {
+	handler slog.Handler
+	level   slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, rec slog.Record) error {
+	return h.handler.Handle(ctx, rec)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{handler: h.handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{handler: h.handler.WithGroup(name), level: h.level}
+}
+
+// multiHandler writes to multiple handlers. A failing handler does not stop
+// the others; all errors are joined.
 type multiHandler struct {
 	handlers []slog.Handler
 }
@@ -139,14 +349,16 @@ func (m *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (m *multiHandler) Handle(ctx context.Context, rec slog.Record) error {
+	var errs []error
 	for _, h := range m.handlers {
 		if h.Enabled(ctx, rec.Level) {
-			if err := h.Handle(ctx, rec); err != nil {
-				return err
+			// Each handler gets its own copy so one cannot alter what the others see
+			if err := h.Handle(ctx, rec.Clone()); err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 func (m *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
@@ -165,24 +377,53 @@ func (m *multiHandler) WithGroup(name string) slog.Handler {
 	return &multiHandler{handlers: newHandlers}
 }
 
-// InitGlobal initializes the global logger
+// InitGlobal initializes the global logger. Later calls keep the first
+// logger until ResetGlobal.
 func InitGlobal(ctx context.Context, config Config) error {
-	var err error
-	once.Do(func() {
-		globalLogger, err = NewLogger(ctx, config)
-		if err == nil {
-			slog.SetDefault(globalLogger.logger)
-		}
-	})
-	return err
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if globalLogger.Load() != nil {
+		return nil
+	}
+	l, err := NewLogger(ctx, config)
+	if err != nil {
+		return err
+	}
+	globalLogger.Store(l)
+	slog.SetDefault(l.logger)
+	return nil
+}
+
+// SetGlobal makes l the global logger and the slog default, replacing any
+// previous one without closing it. Use it to share a logger that already
+// exists instead of having InitGlobal build a second pipeline.
+func SetGlobal(l *Logger) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalLogger.Store(l)
+	slog.SetDefault(l.logger)
+}
+
+// ResetGlobal forgets the global logger so the next InitGlobal creates a new
+// one, and restores the slog and log package defaults. It does not close the
+// previous logger; call Shutdown first. It is safe to call while other
+// goroutines log, but parallel tests still see each other's globals; give
+// them isolated loggers instead.
+func ResetGlobal() {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalLogger.Store(nil)
+	slog.SetDefault(defaultSlog)
+	log.SetOutput(defaultLogOut)
+	log.SetFlags(defaultLogFlags)
 }
 
 // Global returns the global logger instance
 func Global() *Logger {
-	if globalLogger == nil {
-		return &Logger{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	if l := globalLogger.Load(); l != nil {
+		return l
 	}
-	return globalLogger
+	return &Logger{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
 }
 
 // addTraceContext adds trace information from context to log attributes
@@ -243,10 +484,32 @@ func (l *Logger) Critical(msg string, args ...any) {
 	l.logger.Log(context.Background(), slog.Level(LevelCritical), msg, args...)
 }
 
-// Fatal logs at error level and exits
+// Notice logs at notice level (GCP-specific)
+func (l *Logger) Notice(msg string, args ...any) {
+	l.logger.Log(context.Background(), slog.Level(LevelNotice), msg, args...)
+}
+
+// Alert logs at alert level (GCP-specific)
+func (l *Logger) Alert(msg string, args ...any) {
+	l.logger.Log(context.Background(), slog.Level(LevelAlert), msg, args...)
+}
+
+// Emergency logs at emergency level (GCP-specific)
+func (l *Logger) Emergency(msg string, args ...any) {
+	l.logger.Log(context.Background(), slog.Level(LevelEmergency), msg, args...)
+}
+
+// Fatal logs at critical level, runs the exit hooks, drains buffered logs and exits
 func (l *Logger) Fatal(msg string, args ...any) {
-	l.logger.Error(msg, args...)
-	os.Exit(1)
+	l.logger.Log(context.Background(), slog.Level(LevelCritical), msg, args...)
+	l.exit()
+}
+
+// FatalContext logs at critical level with context, runs the exit hooks,
+// drains buffered logs and exits
+func (l *Logger) FatalContext(ctx context.Context, msg string, args ...any) {
+	l.CriticalContext(ctx, msg, args...)
+	l.exit()
 }
 
 // DebugContext logs at debug level with context
@@ -284,6 +547,27 @@ func (l *Logger) CriticalContext(ctx context.Context, msg string, args ...any) {
 	l.logger.Log(ctx, slog.Level(LevelCritical), msg, args...)
 }
 
+// NoticeContext logs at notice level with context
+func (l *Logger) NoticeContext(ctx context.Context, msg string, args ...any) {
+	args = addTraceContext(ctx, l.config.ProjectID, args)
+	ctx = l.enrichContext(ctx)
+	l.logger.Log(ctx, slog.Level(LevelNotice), msg, args...)
+}
+
+// AlertContext logs at alert level with context
+func (l *Logger) AlertContext(ctx context.Context, msg string, args ...any) {
+	args = addTraceContext(ctx, l.config.ProjectID, args)
+	ctx = l.enrichContext(ctx)
+	l.logger.Log(ctx, slog.Level(LevelAlert), msg, args...)
+}
+
+// EmergencyContext logs at emergency level with context
+func (l *Logger) EmergencyContext(ctx context.Context, msg string, args ...any) {
+	args = addTraceContext(ctx, l.config.ProjectID, args)
+	ctx = l.enrichContext(ctx)
+	l.logger.Log(ctx, slog.Level(LevelEmergency), msg, args...)
+}
+
 // enrichContext adds trace information to context for clog/gcp
 func (l *Logger) enrichContext(ctx context.Context) context.Context {
 	span := trace.SpanFromContext(ctx)
@@ -330,31 +614,28 @@ func (l *Logger) addLogToSpan(ctx context.Context, span trace.Span, level Level,
 		return
 	}
 
+	// The span events handler already mirrors this record onto the context span
+	if se := l.config.SpanEvents; se != nil && level >= se.Level && trace.SpanFromContext(ctx) == span {
+		return
+	}
+
 	// Build span attributes from log fields
 	attrs := []attribute.KeyValue{
-		attribute.String("log.severity", slog.Level(level).String()),
+		attribute.String("log.severity", level.String()),
 		attribute.String("log.message", msg),
 	}
 
 	// Add structured log fields as span attributes
-	for i := 0; i < len(args); i += 2 {
-		if i+1 < len(args) {
-			if key, ok := args[i].(string); ok {
-				if !isSystemLogField(key) {
-					if strVal, ok := args[i+1].(string); ok {
-						attrs = append(attrs, attribute.String("log."+key, strVal))
-					} else {
-						attrs = append(attrs, attribute.String("log."+key, fmt.Sprintf("%v", args[i+1])))
-					}
-				}
-			}
+	for _, a := range argsToAttrs(args) {
+		if !isSystemLogField(a.Key) {
+			attrs = append(attrs, attrconv.FromSlog("log."+a.Key, a.Value.Resolve()))
 		}
 	}
 
 	span.AddEvent("log", trace.WithAttributes(attrs...))
 
 	// Mark span as error if this is an error log
-	if level == LevelError || level == LevelCritical {
+	if level >= LevelError {
 		if err != nil {
 			span.RecordError(err, trace.WithAttributes(
 				attribute.String("error.message", err.Error()),
@@ -383,8 +664,9 @@ func (l *Logger) With(args ...any) *Logger {
 	defer l.mu.RUnlock()
 
 	return &Logger{
-		logger: l.logger.With(args...),
-		config: l.config,
+		logger:   l.logger.With(args...),
+		config:   l.config,
+		pipeline: l.pipeline,
 	}
 }
 
@@ -397,8 +679,9 @@ func (l *Logger) WithLogName(logName string) *Logger {
 	newConfig.LogName = logName
 
 	return &Logger{
-		logger: l.logger.With("log_name", logName),
-		config: newConfig,
+		logger:   l.logger.With("log_name", logName),
+		config:   newConfig,
+		pipeline: l.pipeline,
 	}
 }
 
@@ -464,11 +747,13 @@ func (l *Logger) LogError(ctx context.Context, err error, msg string, args ...an
 		return
 	}
 
-	// Add error fields
-	errorArgs := append([]any{
-		"error", err.Error(),
-		"error.type", fmt.Sprintf("%T", err),
-	}, args...)
+	// Add error fields, including fields attached anywhere in the chain
+	errorArgs := append(errorFields(err), args...)
+
+	// Add stack trace for Cloud Error Reporting
+	if stack, ok := l.errorStack(err, 1); ok {
+		errorArgs = append(errorArgs, stackTraceKey, stack)
+	}
 
 	// Add trace context
 	errorArgs = addTraceContext(ctx, l.config.ProjectID, errorArgs)
@@ -493,11 +778,8 @@ func (l *Logger) LogErrorWithSpan(ctx context.Context, span trace.Span, err erro
 		return
 	}
 
-	// Add error fields
-	errorArgs := append([]any{
-		"error", err.Error(),
-		"error.type", fmt.Sprintf("%T", err),
-	}, args...)
+	// Add error fields, including fields attached anywhere in the chain
+	errorArgs := append(errorFields(err), args...)
 
 	// Record error on the provided span
 	if span != nil {
@@ -510,6 +792,11 @@ func (l *Logger) LogErrorWithSpan(ctx context.Context, span trace.Span, err erro
 
 	l.addLogToSpan(ctx, span, LevelError, msg, err, errorArgs...)
 
+	// Add stack trace for Cloud Error Reporting
+	if stack, ok := l.errorStack(err, 1); ok {
+		errorArgs = append(errorArgs, stackTraceKey, stack)
+	}
+
 	// Add trace context
 	errorArgs = addTraceContext(ctx, l.config.ProjectID, errorArgs)
 	ctx = l.enrichContext(ctx)
@@ -517,55 +804,128 @@ func (l *Logger) LogErrorWithSpan(ctx context.Context, span trace.Span, err erro
 	l.logger.ErrorContext(ctx, msg, errorArgs...)
 }
 
+// errorFields returns the log fields describing err: its message and type,
+// the types of the errors it wraps, and every field attached with errs.With
+func errorFields(err error) []any {
+	fields := []any{
+		"error", err.Error(),
+		"error.type", fmt.Sprintf("%T", err),
+	}
+	if types := errs.Types(err); len(types) > 0 {
+		fields = append(fields, "error.chain", types)
+	}
+	for _, a := range errs.Fields(err) {
+		fields = append(fields, a)
+	}
+	return fields
+}
+
+// errorStack returns the innermost stack captured in err's chain. That stack
+// is logged whether or not error reporting is enabled, since errs.New and
+// errs.Wrap capture it on purpose. Without one, the caller's stack is used
+// only when error reporting is enabled. skip is the number of frames to skip,
+// with 0 identifying the caller of errorStack.
+func (l *Logger) errorStack(err error, skip int) (string, bool) {
+	if pcs := errs.Stack(err); pcs != nil {
+		return stack.Format(err.Error(), pcs), true
+	}
+	if l.config.EnableErrorReporting {
+		return formatStack(err.Error(), skip+1), true
+	}
+	return "", false
+}
+
 // LogPanic recovers from panic and logs it with trace correlation
 func (l *Logger) LogPanic(ctx context.Context) {
 	if r := recover(); r != nil {
-		stackTrace := string(debug.Stack())
+		l.LogRecoveredPanic(ctx, r)
+		panic(r) // Re-panic after logging
+	}
+}
 
-		logArgs := []any{
-			"panic.value", fmt.Sprintf("%v", r),
-			"panic.stack_trace", stackTrace,
-		}
+// LogRecoveredPanic logs a panic value the caller already recovered, with
+// trace correlation, and records it on the current span. Call it from the
+// deferred function so the stack trace includes the panicking frames.
+func (l *Logger) LogRecoveredPanic(ctx context.Context, r any) {
+	stackTrace := string(debug.Stack())
 
-		// Add trace context
-		logArgs = addTraceContext(ctx, l.config.ProjectID, logArgs)
-		ctx = l.enrichContext(ctx)
+	logArgs := []any{
+		"panic.value", fmt.Sprintf("%v", r),
+		"panic.stack_trace", stackTrace,
+	}
 
-		// Record panic on current span if available
-		if span := trace.SpanFromContext(ctx); span != nil {
-			span.SetStatus(codes.Error, "panic recovered")
-			span.RecordError(fmt.Errorf("panic: %v", r), trace.WithAttributes(
-				attribute.String("panic.value", fmt.Sprintf("%v", r)),
-				attribute.String("panic.stack_trace", stackTrace),
-			))
-		}
+	// Add stack trace in the panic format Cloud Error Reporting expects
+	if l.config.EnableErrorReporting {
+		logArgs = append(logArgs, stackTraceKey, fmt.Sprintf("panic: %v\n\n%s", r, stackTrace))
+	}
 
-		l.logger.Log(ctx, slog.Level(LevelCritical), "Panic recovered", logArgs...)
-		panic(r) // Re-panic after logging
+	// Add trace context
+	logArgs = addTraceContext(ctx, l.config.ProjectID, logArgs)
+	ctx = l.enrichContext(ctx)
+
+	// Record panic on current span if available
+	if span := trace.SpanFromContext(ctx); span != nil {
+		span.SetStatus(codes.Error, "panic recovered")
+		span.RecordError(fmt.Errorf("panic: %v", r), trace.WithAttributes(
+			attribute.String("panic.value", fmt.Sprintf("%v", r)),
+			attribute.String("panic.stack_trace", stackTrace),
+		))
 	}
+
+	l.logger.Log(ctx, slog.Level(LevelCritical), "Panic recovered", logArgs...)
 }
 
-// SetLevel sets the log level
+// SetLevel sets the log level. The level is shared with loggers derived
+// through With, WithLogName and WithTrace.
 func (l *Logger) SetLevel(level Level) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	l.config.Level = level
-	handler := createHandler(l.config)
-	l.logger = slog.New(handler)
+	if l.pipeline != nil {
+		l.pipeline.level.Set(slog.Level(level))
+	}
 }
 
-// Close properly closes the logger resources
-// With clog/gcp, there are no resources to close (just writing to stderr)
-func (l *Logger) Close() error {
-	// No resources to clean up with clog/gcp
+// AsyncStats returns the async queue counters. It returns zero stats when async is disabled.
+func (l *Logger) AsyncStats() AsyncStats {
+	if l.pipeline == nil || l.pipeline.async == nil {
+		return AsyncStats{}
+	}
+	return l.pipeline.async.stats()
+}
+
+// Shutdown drains buffered records, waiting until they are written or ctx is done.
+// Records logged after Shutdown are written synchronously to the local sinks.
+func (l *Logger) Shutdown(ctx context.Context) error {
+	if l.pipeline == nil {
+		return nil
+	}
+	if l.pipeline.async != nil {
+		if err := l.pipeline.async.close(ctx); err != nil {
+			return fmt.Errorf("failed to drain log queue: %w", err)
+		}
+	}
+	if l.pipeline.cloud != nil {
+		if err := l.pipeline.cloud.close(ctx); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// Shutdown gracefully shuts down the logger
+// Close properly closes the logger resources, draining buffered records
+// for up to defaultCloseTimeout
+func (l *Logger) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCloseTimeout)
+	defer cancel()
+	return l.Shutdown(ctx)
+}
+
+// Shutdown gracefully shuts down the global logger
 func Shutdown(ctx context.Context) error {
-	if globalLogger != nil {
-		return globalLogger.Close()
+	if l := globalLogger.Load(); l != nil {
+		return l.Shutdown(ctx)
 	}
 	return nil
 }
@@ -587,12 +947,19 @@ func captureSourceLocation(skip int) map[string]any {
 }
 
 // Global function shortcuts using the global logger
-func Debug(msg string, args ...any)    { Global().Debug(msg, args...) }
-func Info(msg string, args ...any)     { Global().Info(msg, args...) }
-func Warn(msg string, args ...any)     { Global().Warn(msg, args...) }
-func Error(msg string, args ...any)    { Global().Error(msg, args...) }
-func Critical(msg string, args ...any) { Global().Critical(msg, args...) }
-func Fatal(msg string, args ...any)    { Global().Fatal(msg, args...) }
+func Debug(msg string, args ...any)     { Global().Debug(msg, args...) }
+func Info(msg string, args ...any)      { Global().Info(msg, args...) }
+func Warn(msg string, args ...any)      { Global().Warn(msg, args...) }
+func Error(msg string, args ...any)     { Global().Error(msg, args...) }
+func Critical(msg string, args ...any)  { Global().Critical(msg, args...) }
+func Notice(msg string, args ...any)    { Global().Notice(msg, args...) }
+func Alert(msg string, args ...any)     { Global().Alert(msg, args...) }
+func Emergency(msg string, args ...any) { Global().Emergency(msg, args...) }
+func Fatal(msg string, args ...any)     { Global().Fatal(msg, args...) }
+
+func FatalContext(ctx context.Context, msg string, args ...any) {
+	Global().FatalContext(ctx, msg, args...)
+}
 
 func DebugContext(ctx context.Context, msg string, args ...any) {
 	Global().DebugContext(ctx, msg, args...)
@@ -613,3 +980,15 @@ func ErrorContext(ctx context.Context, msg string, args ...any) {
 func CriticalContext(ctx context.Context, msg string, args ...any) {
 	Global().CriticalContext(ctx, msg, args...)
 }
+
+func NoticeContext(ctx context.Context, msg string, args ...any) {
+	Global().NoticeContext(ctx, msg, args...)
+}
+
+func AlertContext(ctx context.Context, msg string, args ...any) {
+	Global().AlertContext(ctx, msg, args...)
+}
+
+func EmergencyContext(ctx context.Context, msg string, args ...any) {
+	Global().EmergencyContext(ctx, msg, args...)
+}
@@ -1,12 +1,63 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/sirhco/go-gcp-middleware/errs"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// newTestLogger returns a logger built by NewLogger whose only sink writes
+// JSON to buf at config.Level
+func newTestLogger(t *testing.T, config Config, buf *bytes.Buffer) *Logger {
+	t.Helper()
+	config.SetDefaults()
+	config.Handlers = []slog.Handler{slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.Level(config.Level)})}
+	log, err := NewLogger(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+	return log
+}
+
+// decodeEntry decodes the single JSON entry in buf
+func decodeEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode log entry %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+// decodeEntries decodes the JSON entries in buf, one per line
+func decodeEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode log entry %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
 func TestNewLogger(t *testing.T) {
 	ctx := context.Background()
 
@@ -112,6 +163,18 @@ func TestLoggerMethods(t *testing.T) {
 	t.Run("Critical", func(t *testing.T) {
 		log.Critical("critical message", "key", "value")
 	})
+
+	t.Run("Notice", func(t *testing.T) {
+		log.Notice("notice message", "key", "value")
+	})
+
+	t.Run("Alert", func(t *testing.T) {
+		log.Alert("alert message", "key", "value")
+	})
+
+	t.Run("Emergency", func(t *testing.T) {
+		log.Emergency("emergency message", "key", "value")
+	})
 }
 
 func TestLoggerContextMethods(t *testing.T) {
@@ -148,6 +211,18 @@ func TestLoggerContextMethods(t *testing.T) {
 	t.Run("CriticalContext", func(t *testing.T) {
 		log.CriticalContext(ctx, "critical message", "key", "value")
 	})
+
+	t.Run("NoticeContext", func(t *testing.T) {
+		log.NoticeContext(ctx, "notice message", "key", "value")
+	})
+
+	t.Run("AlertContext", func(t *testing.T) {
+		log.AlertContext(ctx, "alert message", "key", "value")
+	})
+
+	t.Run("EmergencyContext", func(t *testing.T) {
+		log.EmergencyContext(ctx, "emergency message", "key", "value")
+	})
 }
 
 func TestLoggerWith(t *testing.T) {
@@ -320,6 +395,91 @@ func TestGlobalLogger(t *testing.T) {
 	}
 }
 
+func TestResetGlobal(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobal()
+	t.Cleanup(ResetGlobal)
+
+	defaultLogger := slog.Default()
+	if err := InitGlobal(ctx, Config{ProjectID: "test-project", ServiceName: "first"}); err != nil {
+		t.Fatalf("InitGlobal() error = %v", err)
+	}
+	first := Global()
+	if slog.Default() == defaultLogger {
+		t.Error("InitGlobal() did not set the slog default")
+	}
+
+	ResetGlobal()
+	if slog.Default() != defaultLogger {
+		t.Error("ResetGlobal() did not restore the slog default")
+	}
+	if err := InitGlobal(ctx, Config{ProjectID: "test-project", ServiceName: "second"}); err != nil {
+		t.Fatalf("InitGlobal() error = %v", err)
+	}
+	if Global() == first || Global().config.ServiceName != "second" {
+		t.Error("InitGlobal() after ResetGlobal() kept the first logger")
+	}
+}
+
+func TestSetGlobal(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobal()
+	t.Cleanup(ResetGlobal)
+
+	var buf bytes.Buffer
+	l, err := NewLogger(ctx, Config{ProjectID: "test-project", Handlers: []slog.Handler{slog.NewJSONHandler(&buf, nil)}})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	SetGlobal(l)
+
+	if Global() != l {
+		t.Error("Global() is not the logger passed to SetGlobal")
+	}
+	slog.Info("through slog")
+	if !strings.Contains(buf.String(), "through slog") {
+		t.Error("SetGlobal() did not install the slog default")
+	}
+	if err := InitGlobal(ctx, Config{ProjectID: "test-project"}); err != nil {
+		t.Fatalf("InitGlobal() error = %v", err)
+	}
+	if Global() != l {
+		t.Error("InitGlobal() replaced the logger installed by SetGlobal")
+	}
+}
+
+func TestResetGlobalConcurrent(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobal()
+	t.Cleanup(ResetGlobal)
+
+	// Run with -race: readers must not race with ResetGlobal and InitGlobal
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					Global().DebugContext(ctx, "concurrent")
+				}
+			}
+		}()
+	}
+	for range 20 {
+		if err := InitGlobal(ctx, Config{ProjectID: "test-project", Handlers: []slog.Handler{slog.NewTextHandler(io.Discard, nil)}}); err != nil {
+			t.Fatalf("InitGlobal() error = %v", err)
+		}
+		ResetGlobal()
+	}
+	close(stop)
+	wg.Wait()
+}
+
 func TestConfigSetDefaults(t *testing.T) {
 	config := Config{}
 	config.SetDefaults()
@@ -358,6 +518,25 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unsupported console format",
+			config: Config{
+				ServiceName:   "test-service",
+				EnableConsole: true,
+				Console:       SinkOptions{Format: "xml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "text format on GCP sink",
+			config: Config{
+				ProjectID:   "test-project",
+				ServiceName: "test-service",
+				EnableGCP:   true,
+				GCP:         SinkOptions{Format: FormatText},
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing ProjectID with GCP disabled",
 			config: Config{
@@ -379,3 +558,118 @@ func TestConfigValidate(t *testing.T) {
 		})
 	}
 }
+
+// failingHandler accepts every record and always fails
+type failingHandler struct {
+	err   error
+	calls int
+}
+
+func (h *failingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *failingHandler) Handle(context.Context, slog.Record) error {
+	h.calls++
+	return h.err
+}
+func (h *failingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *failingHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestMultiHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("failure does not stop other handlers", func(t *testing.T) {
+		var buf bytes.Buffer
+		errA := errors.New("sink a down")
+		errB := errors.New("sink b down")
+		a := &failingHandler{err: errA}
+		b := &failingHandler{err: errB}
+		m := &multiHandler{handlers: []slog.Handler{a, slog.NewJSONHandler(&buf, nil), b}}
+
+		rec := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+		err := m.Handle(ctx, rec)
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("Handle() error = %v, want both sink errors joined", err)
+		}
+		if a.calls != 1 || b.calls != 1 {
+			t.Errorf("calls = %d, %d, want 1, 1", a.calls, b.calls)
+		}
+		if entries := decodeEntries(t, &buf); len(entries) != 1 {
+			t.Errorf("healthy sink wrote %d entries, want 1", len(entries))
+		}
+	})
+
+	t.Run("enabled if any handler is", func(t *testing.T) {
+		debug := slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelDebug})
+		warn := slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn})
+		m := &multiHandler{handlers: []slog.Handler{warn, debug}}
+		if !m.Enabled(ctx, slog.LevelDebug) {
+			t.Error("Enabled(debug) = false, want true")
+		}
+	})
+}
+
+func TestSinkLevels(t *testing.T) {
+	ctx := context.Background()
+	var debugBuf, warnBuf bytes.Buffer
+
+	log, err := NewLogger(ctx, Config{
+		ServiceName: "test-service",
+		Level:       LevelInfo,
+		Handlers: []slog.Handler{
+			slog.NewJSONHandler(&debugBuf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			slog.NewJSONHandler(&warnBuf, &slog.HandlerOptions{Level: slog.LevelWarn}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer log.Close()
+
+	log.Debug("debug message")
+	log.Warn("warn message")
+
+	if got := len(decodeEntries(t, &debugBuf)); got != 2 {
+		t.Errorf("debug sink got %d entries, want 2", got)
+	}
+	if got := len(decodeEntries(t, &warnBuf)); got != 1 {
+		t.Errorf("warn sink got %d entries, want 1", got)
+	}
+}
+
+func TestSinkLevelFallback(t *testing.T) {
+	shared := new(slog.LevelVar)
+	shared.Set(slog.LevelWarn)
+
+	if got := sinkLevel(SinkOptions{}, shared).Level(); got != slog.LevelWarn {
+		t.Errorf("sinkLevel() without override = %v, want %v", got, slog.LevelWarn)
+	}
+	if got := sinkLevel(SinkOptions{Level: LevelDebug}, shared).Level(); got != slog.LevelDebug {
+		t.Errorf("sinkLevel() with override = %v, want %v", got, slog.LevelDebug)
+	}
+}
+
+func TestLogErrorChain(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	log := newTestLogger(t, Config{ServiceName: "svc"}, &buf)
+
+	cause := errs.New("connection reset", "host", "db-1")
+	err := fmt.Errorf("charging card: %w", errs.With(cause, "order_id", 42))
+	log.LogError(ctx, err, "payment failed", "attempt", 2)
+
+	entry := decodeEntry(t, &buf)
+	if entry["order_id"] != float64(42) || entry["host"] != "db-1" || entry["attempt"] != float64(2) {
+		t.Errorf("entry = %v, want chain fields and call fields", entry)
+	}
+	chain, _ := entry["error.chain"].([]any)
+	if len(chain) != 1 || chain[0] != "*fmt.wrapError" {
+		t.Errorf("error.chain = %v", entry["error.chain"])
+	}
+	// errs captured a stack, so it is logged without EnableErrorReporting
+	stack, _ := entry[stackTraceKey].(string)
+	if !strings.HasPrefix(stack, err.Error()+"\n\ngoroutine 1 [running]:\n") {
+		t.Errorf("stack_trace has unexpected header: %q", stack)
+	}
+	if !strings.Contains(stack, "TestLogErrorChain") {
+		t.Errorf("stack_trace does not contain the creation frame: %q", stack)
+	}
+}
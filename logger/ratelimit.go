@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// maxRateLimitKeys bounds the number of tracked (level, message) keys. New
+// keys beyond it are logged without rate limiting until old keys expire.
+const maxRateLimitKeys = 10000
+
+// RateLimitConfig configures flood protection for repeated log lines.
+// Entries are keyed by (level, message); each key may log Burst entries per
+// Interval and further entries are dropped. When the key logs again after the
+// interval, a summary with the number of suppressed entries is emitted first.
+type RateLimitConfig struct {
+	Burst    int           // Entries allowed per key per interval (default: 10)
+	Interval time.Duration // Window length (default: 1s)
+
+	// ErrorBurst is a separate per-key budget for error and critical entries
+	// (default: Burst). Ignored when ExemptErrors is set.
+	ErrorBurst   int
+	ExemptErrors bool // Never rate-limit error and critical entries
+}
+
+// SetDefaults sets reasonable defaults for the rate limit config
+func (c *RateLimitConfig) SetDefaults() {
+	if c.Burst <= 0 {
+		c.Burst = 10
+	}
+	if c.Interval <= 0 {
+		c.Interval = time.Second
+	}
+	if c.ErrorBurst <= 0 {
+		c.ErrorBurst = c.Burst
+	}
+}
+
+type rateLimitKey struct {
+	level slog.Level
+	msg   string
+}
+
+type rateLimitWindow struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+// rateLimiter holds the state shared by a rateLimitHandler and its derived handlers
+type rateLimiter struct {
+	config    RateLimitConfig
+	now       func() time.Time
+	mu        sync.Mutex
+	windows   map[rateLimitKey]*rateLimitWindow
+	lastPrune time.Time
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	config.SetDefaults()
+	return &rateLimiter{
+		config:  config,
+		now:     time.Now,
+		windows: make(map[rateLimitKey]*rateLimitWindow),
+	}
+}
+
+// allow reports whether a record may be logged and how many entries for the
+// same key were suppressed since it last logged
+func (l *rateLimiter) allow(level slog.Level, msg string) (bool, int) {
+	burst := l.config.Burst
+	if level >= slog.LevelError {
+		if l.config.ExemptErrors {
+			return true, 0
+		}
+		burst = l.config.ErrorBurst
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	key := rateLimitKey{level: level, msg: msg}
+	window, ok := l.windows[key]
+	if !ok {
+		if len(l.windows) >= maxRateLimitKeys {
+			l.prune(now)
+			if len(l.windows) >= maxRateLimitKeys {
+				// Too many distinct messages to track: log this one untracked
+				return true, 0
+			}
+		}
+		l.windows[key] = &rateLimitWindow{start: now, count: 1}
+		return true, 0
+	}
+
+	suppressed := 0
+	if now.Sub(window.start) >= l.config.Interval {
+		suppressed = window.suppressed
+		window.start = now
+		window.count = 0
+		window.suppressed = 0
+	}
+
+	if window.count >= burst {
+		window.suppressed++
+		return false, 0
+	}
+	window.count++
+	return true, suppressed
+}
+
+// prune drops expired keys without pending suppressions, at most once per
+// interval so a flood of distinct messages does not rescan the full map
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.config.Interval {
+		return
+	}
+	l.lastPrune = now
+	for key, window := range l.windows {
+		if window.suppressed == 0 && now.Sub(window.start) >= l.config.Interval {
+			delete(l.windows, key)
+		}
+	}
+}
+
+// rateLimitHandler drops repeated (level, message) entries above the configured burst
+type rateLimitHandler struct {
+	handler slog.Handler
+	limiter *rateLimiter
+}
+
+func newRateLimitHandler(handler slog.Handler, config RateLimitConfig) *rateLimitHandler {
+	return &rateLimitHandler{handler: handler, limiter: newRateLimiter(config)}
+}
+
+func (h *rateLimitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *rateLimitHandler) Handle(ctx context.Context, rec slog.Record) error {
+	ok, suppressed := h.limiter.allow(rec.Level, rec.Message)
+	if !ok {
+		return nil
+	}
+
+	if suppressed > 0 {
+		summary := slog.NewRecord(rec.Time, rec.Level, fmt.Sprintf("Suppressed %d similar log entries", suppressed), rec.PC)
+		summary.AddAttrs(
+			slog.Int("suppressed.count", suppressed),
+			slog.String("suppressed.message", rec.Message),
+		)
+		if err := h.handler.Handle(ctx, summary); err != nil {
+			return err
+		}
+	}
+
+	return h.handler.Handle(ctx, rec)
+}
+
+func (h *rateLimitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &rateLimitHandler{handler: h.handler.WithAttrs(attrs), limiter: h.limiter}
+}
+
+func (h *rateLimitHandler) WithGroup(name string) slog.Handler {
+	return &rateLimitHandler{handler: h.handler.WithGroup(name), limiter: h.limiter}
+}
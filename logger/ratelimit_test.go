@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for rate limit tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// newRateLimitedLogger returns a rate-limited test logger driven by a fake clock
+func newRateLimitedLogger(t *testing.T, config RateLimitConfig, buf *bytes.Buffer) (*Logger, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	log := newTestLogger(t, Config{ServiceName: "svc", RateLimit: &config}, buf)
+	log.logger.Handler().(*rateLimitHandler).limiter.now = clock.Now
+	return log, clock
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("suppresses above burst and summarizes", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, clock := newRateLimitedLogger(t, RateLimitConfig{Burst: 2, Interval: time.Second}, &buf)
+
+		for i := 0; i < 5; i++ {
+			log.WarnContext(ctx, "dependency unavailable")
+		}
+		if got := len(decodeEntries(t, &buf)); got != 2 {
+			t.Fatalf("Expected 2 entries within burst, got %d", got)
+		}
+
+		buf.Reset()
+		clock.now = clock.now.Add(time.Second)
+		log.WarnContext(ctx, "dependency unavailable")
+
+		entries := decodeEntries(t, &buf)
+		if len(entries) != 2 {
+			t.Fatalf("Expected summary and entry, got %d entries", len(entries))
+		}
+		if entries[0]["msg"] != "Suppressed 3 similar log entries" {
+			t.Errorf("summary msg = %v", entries[0]["msg"])
+		}
+		if entries[0]["suppressed.message"] != "dependency unavailable" {
+			t.Errorf("suppressed.message = %v", entries[0]["suppressed.message"])
+		}
+	})
+
+	t.Run("keys are per level and message", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, _ := newRateLimitedLogger(t, RateLimitConfig{Burst: 1}, &buf)
+
+		log.InfoContext(ctx, "a")
+		log.InfoContext(ctx, "b")
+		log.WarnContext(ctx, "a")
+		log.InfoContext(ctx, "a")
+
+		if got := len(decodeEntries(t, &buf)); got != 3 {
+			t.Errorf("Expected 3 entries, got %d", got)
+		}
+	})
+
+	t.Run("errors can be exempt", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, _ := newRateLimitedLogger(t, RateLimitConfig{Burst: 1, ExemptErrors: true}, &buf)
+
+		for i := 0; i < 5; i++ {
+			log.ErrorContext(ctx, "write failed")
+		}
+		if got := len(decodeEntries(t, &buf)); got != 5 {
+			t.Errorf("Expected 5 exempt error entries, got %d", got)
+		}
+	})
+
+	t.Run("errors have their own budget", func(t *testing.T) {
+		var buf bytes.Buffer
+		log, _ := newRateLimitedLogger(t, RateLimitConfig{Burst: 1, ErrorBurst: 3}, &buf)
+
+		for i := 0; i < 5; i++ {
+			log.ErrorContext(ctx, "write failed")
+		}
+		if got := len(decodeEntries(t, &buf)); got != 3 {
+			t.Errorf("Expected 3 error entries, got %d", got)
+		}
+	})
+}
+
+func TestRateLimitKeyCap(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	limiter := newRateLimiter(RateLimitConfig{Burst: 1, Interval: time.Second})
+	limiter.now = clock.Now
+
+	for i := range maxRateLimitKeys + 100 {
+		if ok, _ := limiter.allow(slog.LevelInfo, fmt.Sprintf("message %d", i)); !ok {
+			t.Fatalf("allow(message %d) = false, want distinct messages logged", i)
+		}
+	}
+	if n := len(limiter.windows); n > maxRateLimitKeys {
+		t.Errorf("tracked keys = %d, want at most %d", n, maxRateLimitKeys)
+	}
+
+	// Expired keys make room again after the interval
+	clock.now = clock.now.Add(time.Second)
+	limiter.allow(slog.LevelInfo, "new message")
+	if _, ok := limiter.windows[rateLimitKey{level: slog.LevelInfo, msg: "new message"}]; !ok {
+		t.Error("new key not tracked after expired keys were pruned")
+	}
+	if n := len(limiter.windows); n != 1 {
+		t.Errorf("tracked keys = %d after pruning, want 1", n)
+	}
+}
+
+func TestRateLimitConfigSetDefaults(t *testing.T) {
+	config := RateLimitConfig{}
+	config.SetDefaults()
+
+	if config.Burst != 10 {
+		t.Errorf("Burst = %d, want 10", config.Burst)
+	}
+	if config.Interval != time.Second {
+		t.Errorf("Interval = %v, want 1s", config.Interval)
+	}
+	if config.ErrorBurst != config.Burst {
+		t.Errorf("ErrorBurst = %d, want %d", config.ErrorBurst, config.Burst)
+	}
+}
@@ -0,0 +1,317 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RedactMode controls how matched values are replaced
+type RedactMode int
+
+const (
+	// RedactMask replaces matched values with a fixed placeholder
+	RedactMask RedactMode = iota
+	// RedactHash replaces matched values with a truncated SHA-256 hash so
+	// equal values can still be correlated across entries
+	RedactHash
+)
+
+// redactedPlaceholder replaces values whose key matched a key rule
+const redactedPlaceholder = "[REDACTED]"
+
+// ValueRule matches sensitive content inside string values
+type ValueRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Validate optionally confirms a pattern match, e.g. a Luhn check for card numbers
+	Validate func(match string) bool
+}
+
+// Built-in value rules
+var (
+	RuleEmail = ValueRule{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	RuleJWT = ValueRule{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`),
+	}
+	RuleBearerToken = ValueRule{
+		Name:    "bearer",
+		Pattern: regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=\-]+`),
+	}
+	RuleCardNumber = ValueRule{
+		Name:     "pan",
+		Pattern:  regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		Validate: luhnValid,
+	}
+	RuleGCPAPIKey = ValueRule{
+		Name:    "gcp_api_key",
+		Pattern: regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`),
+	}
+)
+
+// RedactConfig configures redaction of sensitive data in logs and spans
+type RedactConfig struct {
+	// Keys are case-insensitive key patterns whose values are always redacted.
+	// '*' matches any sequence of characters, e.g. "*token*".
+	Keys []string
+	// Rules match sensitive content inside string values regardless of key
+	Rules []ValueRule
+	// Mode selects masking (default) or hashing of matched values
+	Mode RedactMode
+}
+
+// DefaultRedactConfig returns a RedactConfig covering common credentials and PII
+func DefaultRedactConfig() *RedactConfig {
+	return &RedactConfig{
+		Keys: []string{
+			"password", "passwd", "secret", "*secret*", "authorization", "cookie", "set-cookie",
+			"*token*", "api_key", "apikey", "x-api-key", "private_key",
+		},
+		Rules: []ValueRule{RuleJWT, RuleBearerToken, RuleEmail, RuleCardNumber, RuleGCPAPIKey},
+		Mode:  RedactMask,
+	}
+}
+
+// Redactor applies redaction rules to log attributes, strings and span attributes
+type Redactor struct {
+	keys  []string
+	rules []ValueRule
+	mode  RedactMode
+}
+
+// NewRedactor creates a Redactor from the given config
+func NewRedactor(config RedactConfig) *Redactor {
+	keys := make([]string, len(config.Keys))
+	for i, k := range config.Keys {
+		keys[i] = strings.ToLower(k)
+	}
+	return &Redactor{keys: keys, rules: config.Rules, mode: config.Mode}
+}
+
+// matchKey reports whether a key matches a key rule. Dotted keys are also
+// matched on their last segment so "user.password" matches "password".
+func (r *Redactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	leaf := key
+	if i := strings.LastIndexAny(key, "./"); i >= 0 {
+		leaf = key[i+1:]
+	}
+	for _, pattern := range r.keys {
+		if globMatch(pattern, key) || globMatch(pattern, leaf) {
+			return true
+		}
+	}
+	return false
+}
+
+// replace returns the replacement for a sensitive value
+func (r *Redactor) replace(value, label string) string {
+	if r.mode == RedactHash {
+		sum := sha256.Sum256([]byte(value))
+		return "[sha256:" + hex.EncodeToString(sum[:6]) + "]"
+	}
+	if label == "" {
+		return redactedPlaceholder
+	}
+	return "[REDACTED:" + label + "]"
+}
+
+// RedactString replaces every value rule match in s
+func (r *Redactor) RedactString(s string) string {
+	for _, rule := range r.rules {
+		s = rule.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if rule.Validate != nil && !rule.Validate(match) {
+				return match
+			}
+			return r.replace(match, rule.Name)
+		})
+	}
+	return s
+}
+
+// RedactAttr redacts a log attribute, recursing into groups, maps and slices
+func (r *Redactor) RedactAttr(a slog.Attr) slog.Attr {
+	if r.matchKey(a.Key) {
+		return slog.String(a.Key, r.replace(a.Value.Resolve().String(), ""))
+	}
+	return slog.Attr{Key: a.Key, Value: r.redactValue(a.Value.Resolve())}
+}
+
+func (r *Redactor) redactValue(v slog.Value) slog.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.StringValue(r.RedactString(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, a := range group {
+			attrs[i] = r.RedactAttr(a)
+		}
+		return slog.GroupValue(attrs...)
+	case slog.KindAny:
+		return slog.AnyValue(r.redactAny(v.Any()))
+	default:
+		return v
+	}
+}
+
+// redactAny redacts the common dynamic shapes passed to loggers, such as the httpRequest map
+func (r *Redactor) redactAny(v any) any {
+	switch val := v.(type) {
+	case string:
+		return r.RedactString(val)
+	case error:
+		return r.RedactString(val.Error())
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			if r.matchKey(k) {
+				out[k] = r.replace(fmt.Sprint(item), "")
+				continue
+			}
+			out[k] = r.redactAny(item)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(val))
+		for k, item := range val {
+			if r.matchKey(k) {
+				out[k] = r.replace(item, "")
+				continue
+			}
+			out[k] = r.RedactString(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.redactAny(item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(val))
+		for i, item := range val {
+			out[i] = r.RedactString(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// RedactAttributes redacts span attributes. It satisfies telemetry.AttributeRedactor.
+func (r *Redactor) RedactAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, len(attrs))
+	for i, kv := range attrs {
+		key := string(kv.Key)
+		switch {
+		case r.matchKey(key):
+			out[i] = attribute.String(key, r.replace(kv.Value.Emit(), ""))
+		case kv.Value.Type() == attribute.STRING:
+			out[i] = attribute.String(key, r.RedactString(kv.Value.AsString()))
+		case kv.Value.Type() == attribute.STRINGSLICE:
+			values := kv.Value.AsStringSlice()
+			for j := range values {
+				values[j] = r.RedactString(values[j])
+			}
+			out[i] = attribute.StringSlice(key, values)
+		default:
+			out[i] = kv
+		}
+	}
+	return out
+}
+
+// redactHandler scrubs sensitive keys and values before records reach the sinks
+type redactHandler struct {
+	handler  slog.Handler
+	redactor *Redactor
+}
+
+func newRedactHandler(handler slog.Handler, redactor *Redactor) *redactHandler {
+	return &redactHandler{handler: handler, redactor: redactor}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, rec slog.Record) error {
+	redacted := slog.NewRecord(rec.Time, rec.Level, h.redactor.RedactString(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.RedactAttr(a))
+		return true
+	})
+	return h.handler.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactor.RedactAttr(a)
+	}
+	return &redactHandler{handler: h.handler.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{handler: h.handler.WithGroup(name), redactor: h.redactor}
+}
+
+// globMatch matches s against a pattern where '*' matches any sequence of characters
+func globMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+// luhnValid reports whether the digits in s form a 13-19 digit number passing the Luhn check
+func luhnValid(s string) bool {
+	var digits []int
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
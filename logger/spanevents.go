@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/sirhco/go-gcp-middleware/internal/attrconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// spanEventName is the name of events created from log records
+	spanEventName = "log"
+
+	// defaultMaxEventsPerSpan caps log events added to a single span
+	defaultMaxEventsPerSpan = 128
+
+	// maxTrackedSpans is the number of spans tracked before ended spans are pruned
+	maxTrackedSpans = 10000
+
+	// droppedEventsKey records how many log events a span did not receive
+	droppedEventsKey = "log.dropped_events"
+)
+
+// SpanEventsConfig mirrors log records onto the active span as "log" events
+type SpanEventsConfig struct {
+	Level            Level // Minimum level mirrored (default: Info)
+	MaxEventsPerSpan int   // Events added per span before the rest are counted as dropped (default: 128)
+}
+
+// SetDefaults applies default values to unset fields
+func (c *SpanEventsConfig) SetDefaults() {
+	if c.MaxEventsPerSpan <= 0 {
+		c.MaxEventsPerSpan = defaultMaxEventsPerSpan
+	}
+}
+
+// spanCounter counts the events added to one span
+type spanCounter struct {
+	span    trace.Span
+	events  int
+	dropped int
+}
+
+// spanEventTracker enforces the per-span event cap
+type spanEventTracker struct {
+	mu    sync.Mutex
+	max   int
+	spans map[trace.SpanID]*spanCounter
+}
+
+// acquire reports whether another event may be added to span. When the cap
+// is reached it returns false and the updated dropped count.
+func (t *spanEventTracker) acquire(span trace.Span) (bool, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := span.SpanContext().SpanID()
+	c, ok := t.spans[id]
+	if !ok {
+		if len(t.spans) >= maxTrackedSpans {
+			t.prune()
+		}
+		c = &spanCounter{span: span}
+		t.spans[id] = c
+	}
+	if c.events >= t.max {
+		c.dropped++
+		return false, c.dropped
+	}
+	c.events++
+	return true, 0
+}
+
+// prune forgets spans that have ended
+func (t *spanEventTracker) prune() {
+	for id, c := range t.spans {
+		if !c.span.IsRecording() {
+			delete(t.spans, id)
+		}
+	}
+}
+
+// spanEventsHandler mirrors records onto the span in the context
+type spanEventsHandler struct {
+	handler  slog.Handler
+	level    slog.Level
+	redactor *Redactor
+	tracker  *spanEventTracker
+	attrs    []attribute.KeyValue
+	prefix   string
+}
+
+// newSpanEventsHandler wraps h; redactor may be nil
+func newSpanEventsHandler(h slog.Handler, config SpanEventsConfig, redactor *Redactor) *spanEventsHandler {
+	config.SetDefaults()
+	return &spanEventsHandler{
+		handler:  h,
+		level:    slog.Level(config.Level),
+		redactor: redactor,
+		tracker:  &spanEventTracker{max: config.MaxEventsPerSpan, spans: make(map[trace.SpanID]*spanCounter)},
+	}
+}
+
+// Enabled also accepts records the sinks would drop when there is a recording span to receive them
+func (h *spanEventsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level) || h.mirrors(ctx, level)
+}
+
+func (h *spanEventsHandler) mirrors(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && trace.SpanFromContext(ctx).IsRecording()
+}
+
+func (h *spanEventsHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if h.mirrors(ctx, rec.Level) {
+		h.addEvent(trace.SpanFromContext(ctx), rec)
+	}
+	if !h.handler.Enabled(ctx, rec.Level) {
+		return nil
+	}
+	return h.handler.Handle(ctx, rec)
+}
+
+// addEvent adds rec to span as a log event and marks the span failed for errors
+func (h *spanEventsHandler) addEvent(span trace.Span, rec slog.Record) {
+	if rec.Level >= slog.LevelError {
+		span.SetStatus(codes.Error, rec.Message)
+	}
+
+	ok, dropped := h.tracker.acquire(span)
+	if !ok {
+		span.SetAttributes(attribute.Int(droppedEventsKey, dropped))
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 2+len(h.attrs)+rec.NumAttrs())
+	attrs = append(attrs,
+		attribute.String("log.severity", Level(rec.Level).String()),
+		attribute.String("log.message", rec.Message),
+	)
+	attrs = append(attrs, h.attrs...)
+	rec.Attrs(func(a slog.Attr) bool {
+		attrs = h.appendAttr(attrs, h.prefix, a)
+		return true
+	})
+
+	opts := []trace.EventOption{trace.WithAttributes(attrs...)}
+	if !rec.Time.IsZero() {
+		opts = append(opts, trace.WithTimestamp(rec.Time))
+	}
+	span.AddEvent(spanEventName, opts...)
+}
+
+// appendAttr converts a to typed span attributes under "log.", flattening groups
+func (h *spanEventsHandler) appendAttr(attrs []attribute.KeyValue, prefix string, a slog.Attr) []attribute.KeyValue {
+	if isSystemLogField(a.Key) || a.Key == stackTraceKey {
+		return attrs
+	}
+	if h.redactor != nil {
+		a = h.redactor.RedactAttr(a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = h.appendAttr(attrs, groupPrefix, ga)
+		}
+		return attrs
+	}
+	return append(attrs, attrconv.FromSlog("log."+prefix+a.Key, a.Value))
+}
+
+func (h *spanEventsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.handler = h.handler.WithAttrs(attrs)
+	h2.attrs = append([]attribute.KeyValue(nil), h.attrs...)
+	for _, a := range attrs {
+		h2.attrs = h.appendAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *spanEventsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.handler = h.handler.WithGroup(name)
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// eventAttrs returns the attributes of a span event by key
+func eventAttrs(e sdktrace.Event) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(e.Attributes))
+	for _, kv := range e.Attributes {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestSpanEventsHandler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	t.Run("typed attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{SpanEvents: &SpanEventsConfig{}}, &buf)
+
+		ctx, span := tracer.Start(context.Background(), "op")
+		log.With("component", "db").InfoContext(ctx, "query done",
+			"rows", 3,
+			"ratio", 0.5,
+			"cached", true,
+			"took", 15*time.Millisecond,
+			"tables", []string{"users", "orders"},
+			"trace_id", "should-be-skipped",
+			slog.Group("conn", "host", "db-1"),
+		)
+		span.End()
+
+		ended := recorder.Ended()
+		events := ended[len(ended)-1].Events()
+		if len(events) != 1 || events[0].Name != spanEventName {
+			t.Fatalf("events = %+v, want one log event", events)
+		}
+		attrs := eventAttrs(events[0])
+
+		checks := map[attribute.Key]attribute.Value{
+			"log.severity":  attribute.StringValue("INFO"),
+			"log.message":   attribute.StringValue("query done"),
+			"log.component": attribute.StringValue("db"),
+			"log.rows":      attribute.Int64Value(3),
+			"log.ratio":     attribute.Float64Value(0.5),
+			"log.cached":    attribute.BoolValue(true),
+			"log.took":      attribute.StringValue("15ms"),
+			"log.tables":    attribute.StringSliceValue([]string{"users", "orders"}),
+			"log.conn.host": attribute.StringValue("db-1"),
+		}
+		for k, want := range checks {
+			if got := attrs[k]; got.Type() != want.Type() || got.Emit() != want.Emit() {
+				t.Errorf("%s = %v, want %v", k, got.Emit(), want.Emit())
+			}
+		}
+		if _, ok := attrs["log.trace_id"]; ok {
+			t.Error("system field trace_id was mirrored")
+		}
+		if buf.Len() == 0 {
+			t.Error("record was not written to the sink")
+		}
+	})
+
+	t.Run("level threshold", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{Level: LevelDebug, SpanEvents: &SpanEventsConfig{Level: LevelWarn}}, &buf)
+
+		ctx, span := tracer.Start(context.Background(), "op")
+		log.InfoContext(ctx, "info")
+		log.WarnContext(ctx, "warn")
+		span.End()
+
+		ended := recorder.Ended()
+		if events := ended[len(ended)-1].Events(); len(events) != 1 {
+			t.Errorf("got %d events, want 1", len(events))
+		}
+	})
+
+	t.Run("mirrors records below the sink level", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{Level: LevelWarn, SpanEvents: &SpanEventsConfig{}}, &buf)
+
+		ctx, span := tracer.Start(context.Background(), "op")
+		log.InfoContext(ctx, "span only")
+		span.End()
+
+		ended := recorder.Ended()
+		if events := ended[len(ended)-1].Events(); len(events) != 1 {
+			t.Errorf("got %d events, want 1", len(events))
+		}
+		if buf.Len() != 0 {
+			t.Errorf("sink below its level wrote %q", buf.String())
+		}
+	})
+
+	t.Run("error sets span status", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{SpanEvents: &SpanEventsConfig{}}, &buf)
+
+		ctx, span := tracer.Start(context.Background(), "op")
+		log.ErrorContext(ctx, "payment failed", "error", errors.New("card declined"))
+		span.End()
+
+		ended := recorder.Ended()
+		got := ended[len(ended)-1]
+		if got.Status().Code != codes.Error || got.Status().Description != "payment failed" {
+			t.Errorf("status = %+v, want error", got.Status())
+		}
+		if v := eventAttrs(got.Events()[0])["log.error"]; v.AsString() != "card declined" {
+			t.Errorf("log.error = %q", v.AsString())
+		}
+	})
+
+	t.Run("per-span cap", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{SpanEvents: &SpanEventsConfig{MaxEventsPerSpan: 2}}, &buf)
+
+		ctx, span := tracer.Start(context.Background(), "op")
+		for range 5 {
+			log.InfoContext(ctx, "tick")
+		}
+		span.End()
+
+		ended := recorder.Ended()
+		got := ended[len(ended)-1]
+		if n := len(got.Events()); n != 2 {
+			t.Errorf("got %d events, want 2", n)
+		}
+		var dropped int64
+		for _, kv := range got.Attributes() {
+			if kv.Key == droppedEventsKey {
+				dropped = kv.Value.AsInt64()
+			}
+		}
+		if dropped != 3 {
+			t.Errorf("%s = %d, want 3", droppedEventsKey, dropped)
+		}
+	})
+
+	t.Run("redacts mirrored attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{SpanEvents: &SpanEventsConfig{}, Redact: &RedactConfig{Keys: []string{"password"}}}, &buf)
+
+		ctx, span := tracer.Start(context.Background(), "op")
+		log.InfoContext(ctx, "login", "password", "hunter2")
+		span.End()
+
+		ended := recorder.Ended()
+		if v := eventAttrs(ended[len(ended)-1].Events()[0])["log.password"]; v.AsString() == "hunter2" {
+			t.Error("password was mirrored unredacted")
+		}
+	})
+
+	t.Run("WithSpan does not duplicate events", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{SpanEvents: &SpanEventsConfig{}}, &buf)
+
+		ctx, span := tracer.Start(context.Background(), "op")
+		log.InfoWithSpan(ctx, span, "once")
+		span.End()
+
+		ended := recorder.Ended()
+		if events := ended[len(ended)-1].Events(); len(events) != 1 {
+			t.Errorf("got %d events, want 1", len(events))
+		}
+	})
+
+	t.Run("no span", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newTestLogger(t, Config{Level: LevelWarn, SpanEvents: &SpanEventsConfig{}}, &buf)
+		if log.logger.Enabled(context.Background(), slog.LevelInfo) {
+			t.Error("Enabled() = true without a recording span")
+		}
+	})
+}
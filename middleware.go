@@ -12,12 +12,19 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // Logging middleware logs HTTP requests with tracing integration
 func Logging(next http.Handler) http.Handler {
+	return logRequests(next, logger.Global)
+}
+
+// logRequests implements Logging, writing to the logger returned by log
+func logRequests(next http.Handler, log func() *logger.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
@@ -27,8 +34,13 @@ func Logging(next http.Handler) http.Handler {
 		// Get span from context for enhanced logging
 		span := trace.SpanFromContext(r.Context())
 
+		// Seed the request's wide event so attributes added by handlers
+		// with logger.WithAttrs end up on the completion log line
+		ctx := logger.StartWideEvent(r.Context())
+		ctx = logger.WithAttrs(ctx, requestAttrs(w, r)...)
+
 		// Call the next handler
-		next.ServeHTTP(wrapped, r)
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
 
 		// Log the request details using enhanced logger with trace correlation
 		duration := time.Since(start)
@@ -49,18 +61,74 @@ func Logging(next http.Handler) http.Handler {
 			}
 		}
 
-		logger.Global().LogHTTPRequest(
-			r.Context(),
+		// Emit the canonical wide event with every attribute added during the request
+		logArgs := []any{
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		}
+		for _, attr := range logger.WideEventAttrs(ctx) {
+			logArgs = append(logArgs, attr)
+		}
+
+		log().LogHTTPRequest(
+			ctx,
 			r.Method,
 			r.RequestURI,
 			wrapped.statusCode,
 			duration,
-			"remote_addr", r.RemoteAddr,
-			"user_agent", r.UserAgent(),
+			logArgs...,
 		)
 	})
 }
 
+// requestAttrs returns the request-scoped attributes the Logging middleware seeds into the context
+func requestAttrs(w http.ResponseWriter, r *http.Request) []any {
+	var attrs []any
+
+	// RequestID runs before Logging and sets the response header
+	requestID := w.Header().Get("X-Request-ID")
+	if requestID == "" {
+		requestID = r.Header.Get("X-Request-ID")
+	}
+	if requestID != "" {
+		attrs = append(attrs, "request_id", requestID)
+	}
+	if r.Pattern != "" {
+		attrs = append(attrs, "http.route", r.Pattern)
+	}
+
+	return attrs
+}
+
+// Baggage middleware keeps only the allowlisted baggage members received
+// from the caller, within the configured size limit, and adds them to the
+// active span and to logs made with the request context. It belongs after
+// OTelHTTP, which extracts the baggage, and before Logging.
+func Baggage(config telemetry.BaggageConfig) func(http.Handler) http.Handler {
+	config.SetDefaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			trusted := config.Filter(baggage.FromContext(ctx))
+			ctx = baggage.ContextWithBaggage(ctx, trusted)
+
+			members := trusted.Members()
+			if len(members) > 0 {
+				attrs := make([]attribute.KeyValue, 0, len(members))
+				args := make([]any, 0, 2*len(members))
+				for _, m := range members {
+					attrs = append(attrs, attribute.String(m.Key(), m.Value()))
+					args = append(args, m.Key(), m.Value())
+				}
+				trace.SpanFromContext(ctx).SetAttributes(attrs...)
+				ctx = logger.WithAttrs(ctx, args...)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // CORS middleware adds CORS headers to HTTP responses with tracing.
 // It allows all origins and handles preflight OPTIONS requests for cross-origin resource sharing.
 func CORS(next http.Handler) http.Handler {
@@ -106,19 +174,16 @@ func CorsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 // Recovery middleware recovers from panics with tracing integration
 func Recovery(next http.Handler) http.Handler {
+	return recoverPanics(next, logger.Global)
+}
+
+// recoverPanics implements Recovery, writing to the logger returned by log
+func recoverPanics(next http.Handler, log func() *logger.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				// Record panic in span
-				if span := trace.SpanFromContext(r.Context()); span != nil {
-					span.SetStatus(codes.Error, "panic recovered")
-					span.RecordError(fmt.Errorf("panic: %v", err), trace.WithAttributes(
-						attribute.String("panic.value", fmt.Sprintf("%v", err)),
-					))
-				}
-
-				// Log the panic using enhanced logger with trace correlation
-				logger.Global().LogPanic(r.Context())
+				// Log the panic with trace correlation and record it in the span
+				log().LogRecoveredPanic(r.Context(), err)
 
 				// Return a 500 error
 				w.Header().Set("Content-Type", "application/json")
@@ -200,11 +265,16 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 
 // OTelHTTP wraps a handler with OpenTelemetry HTTP instrumentation
 func OTelHTTP(operation string) func(http.Handler) http.Handler {
+	return otelHTTP(operation, otel.GetTracerProvider(), otel.GetTextMapPropagator())
+}
+
+// otelHTTP implements OTelHTTP with the given tracer provider and propagator
+func otelHTTP(operation string, tp trace.TracerProvider, propagator propagation.TextMapPropagator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return otelhttp.NewHandler(next, operation,
-			otelhttp.WithTracerProvider(otel.GetTracerProvider()),
+			otelhttp.WithTracerProvider(tp),
 			otelhttp.WithMeterProvider(otel.GetMeterProvider()),
-			otelhttp.WithPropagators(otel.GetTextMapPropagator()),
+			otelhttp.WithPropagators(propagator),
 		)
 	}
 }
@@ -227,13 +297,8 @@ func CustomSpan(spanName string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx, span := telemetry.StartSpanFromContext(r.Context(), spanName, telemetry.SpanOptions{
-				Kind: trace.SpanKindServer,
-				Attributes: []attribute.KeyValue{
-					attribute.String("http.method", r.Method),
-					attribute.String("http.url", r.RequestURI),
-					attribute.String("http.scheme", r.URL.Scheme),
-					attribute.String("http.host", r.Host),
-				},
+				Kind:       trace.SpanKindServer,
+				Attributes: customSpanAttrs(r),
 			})
 			defer span.End()
 
@@ -243,3 +308,13 @@ func CustomSpan(spanName string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// customSpanAttrs returns the request attributes recorded by CustomSpan
+func customSpanAttrs(r *http.Request) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("http.method", r.Method),
+		attribute.String("http.url", r.RequestURI),
+		attribute.String("http.scheme", r.URL.Scheme),
+		attribute.String("http.host", r.Host),
+	}
+}
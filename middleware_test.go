@@ -1,18 +1,49 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sirhco/go-gcp-middleware/logger"
 	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/trace"
 )
 
+func TestBaggage(t *testing.T) {
+	b, err := baggage.Parse("tenant.id=acme,user.id=42,role=admin")
+	if err != nil {
+		t.Fatalf("baggage.Parse() error = %v", err)
+	}
+	ctx := baggage.ContextWithBaggage(context.Background(), b)
+
+	var got context.Context
+	handler := Baggage(telemetry.BaggageConfig{Keys: []string{"tenant.id", "user.id"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Context()
+		}),
+	)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	if trusted := baggage.FromContext(got); trusted.Len() != 2 || trusted.Member("role").Key() != "" {
+		t.Errorf("baggage = %q, want only allowlisted members", trusted.String())
+	}
+	attrs := map[string]string{}
+	for _, a := range logger.AttrsFromContext(got) {
+		attrs[a.Key] = a.Value.String()
+	}
+	if attrs["tenant.id"] != "acme" || attrs["user.id"] != "42" || attrs["role"] != "" {
+		t.Errorf("log attrs = %v, want tenant.id and user.id only", attrs)
+	}
+}
+
 func TestCORS(t *testing.T) {
 	handler := CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -150,6 +181,60 @@ func TestLogging(t *testing.T) {
 	}
 }
 
+func TestLoggingWideEvent(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := logger.NewLogger(context.Background(), logger.Config{
+		ProjectID: "test-project",
+		Handlers:  []slog.Handler{slog.NewJSONHandler(&buf, nil)},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	var eventAttrs map[string]string
+	handler := RequestID(logRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := logger.WithAttrs(r.Context(), "order_id", "42")
+		if got := contextAttr(ctx, "request_id"); got != "req-123" {
+			t.Errorf("request_id in context = %q, want req-123", got)
+		}
+
+		eventAttrs = make(map[string]string)
+		for _, a := range logger.WideEventAttrs(ctx) {
+			eventAttrs[a.Key] = a.Value.String()
+		}
+		w.WriteHeader(http.StatusOK)
+	}), func() *logger.Logger { return log }))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if eventAttrs["request_id"] != "req-123" || eventAttrs["order_id"] != "42" {
+		t.Errorf("wide event attrs = %v, want request_id and order_id", eventAttrs)
+	}
+
+	// The canonical request line carries what the handler added
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var line map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &line); err != nil {
+		t.Fatalf("decode request log %q: %v", buf.String(), err)
+	}
+	if line["order_id"] != "42" || line["request_id"] != "req-123" {
+		t.Errorf("request log = %v, want order_id and request_id", line)
+	}
+}
+
+func contextAttr(ctx context.Context, key string) string {
+	for _, a := range logger.AttrsFromContext(ctx) {
+		if a.Key == key {
+			return a.Value.String()
+		}
+	}
+	return ""
+}
+
 func TestRequestID(t *testing.T) {
 	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Option configures a Client created by New
+type Option func(*Config)
+
+// New creates a Client from options applied in order to an empty Config,
+// then validated as by NewClient.
+//
+//	client, err := middleware.New(ctx,
+//		middleware.WithConfig(middleware.ProductionConfig()),
+//		middleware.WithServiceName("orders"),
+//		middleware.WithSpanExporter(exporter),
+//	)
+func New(ctx context.Context, opts ...Option) (*Client, error) {
+	var config Config
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return NewClient(ctx, config)
+}
+
+// WithConfig replaces the configuration built so far with c. Options after it
+// override its fields.
+func WithConfig(c Config) Option {
+	return func(config *Config) {
+		*config = c
+	}
+}
+
+// WithServiceName sets the service name
+func WithServiceName(name string) Option {
+	return func(c *Config) {
+		c.ServiceName = name
+	}
+}
+
+// WithServiceVersion sets the service version
+func WithServiceVersion(version string) Option {
+	return func(c *Config) {
+		c.ServiceVersion = version
+	}
+}
+
+// WithEnvironment sets the deployment environment
+func WithEnvironment(env string) Option {
+	return func(c *Config) {
+		c.Environment = env
+	}
+}
+
+// WithProjectID sets the GCP project ID
+func WithProjectID(projectID string) Option {
+	return func(c *Config) {
+		c.ProjectID = projectID
+	}
+}
+
+// WithLogLevel sets the minimum log level
+func WithLogLevel(level logger.Level) Option {
+	return func(c *Config) {
+		c.LogLevel = level
+	}
+}
+
+// WithConsole enables console logging with the given sink options
+func WithConsole(opts logger.SinkOptions) Option {
+	return func(c *Config) {
+		c.EnableConsole = true
+		c.ConsoleSink = opts
+	}
+}
+
+// WithGCPLogging enables GCP structured logging with the given sink options
+func WithGCPLogging(opts logger.SinkOptions) Option {
+	return func(c *Config) {
+		c.EnableGCP = true
+		c.GCPSink = opts
+	}
+}
+
+// WithLogHandler adds h as a log sink. It may be given more than once.
+func WithLogHandler(h slog.Handler) Option {
+	return func(c *Config) {
+		c.LogHandlers = append(c.LogHandlers, h)
+	}
+}
+
+// WithTracing enables tracing with the built-in exporter and the given sampling ratio
+func WithTracing(exporter telemetry.Exporter, ratio float64) Option {
+	return func(c *Config) {
+		c.EnableTracing = true
+		c.TraceExporter = exporter
+		c.TraceRatio = ratio
+	}
+}
+
+// WithBaggage trusts the allowlisted baggage members from callers and copies
+// them onto spans and logs
+func WithBaggage(config telemetry.BaggageConfig) Option {
+	return func(c *Config) {
+		c.Baggage = &config
+	}
+}
+
+// WithSpanExporter enables tracing and sends spans to exporter instead of
+// Cloud Trace. The Client's Shutdown shuts the exporter down.
+func WithSpanExporter(exporter sdktrace.SpanExporter) Option {
+	return func(c *Config) {
+		c.EnableTracing = true
+		c.SpanExporter = exporter
+	}
+}
+
+// WithSampler enables tracing and uses sampler instead of TraceRatio
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return func(c *Config) {
+		c.EnableTracing = true
+		c.Sampler = sampler
+	}
+}
+
+// WithResource uses res instead of the detected service and GCP resource
+func WithResource(res *resource.Resource) Option {
+	return func(c *Config) {
+		c.Resource = res
+	}
+}
+
+// WithPropagator uses p instead of the W3C trace context and baggage propagator
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *Config) {
+		c.Propagator = p
+	}
+}
+
+// WithIDGenerator uses gen to create trace and span IDs
+func WithIDGenerator(gen sdktrace.IDGenerator) Option {
+	return func(c *Config) {
+		c.IDGenerator = gen
+	}
+}
+
+// WithIsolation keeps the client's logger and tracer provider out of the globals
+func WithIsolation() Option {
+	return func(c *Config) {
+		c.Isolated = true
+	}
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// flushSpans exports the client's ended spans without shutting the exporter down
+func flushSpans(t *testing.T, client *Client) {
+	t.Helper()
+	tp, ok := client.Telemetry().TracerProvider().(*sdktrace.TracerProvider)
+	if !ok {
+		t.Fatal("client has no SDK tracer provider")
+	}
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() error = %v", err)
+	}
+}
+
+// fixedIDs generates the same trace ID for every trace
+type fixedIDs struct {
+	traceID trace.TraceID
+	next    byte
+}
+
+func (g *fixedIDs) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	return g.traceID, g.NewSpanID(ctx, g.traceID)
+}
+
+func (g *fixedIDs) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	g.next++
+	return trace.SpanID{7: g.next}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobals()
+	t.Cleanup(ResetGlobals)
+
+	var buf bytes.Buffer
+	exporter := tracetest.NewInMemoryExporter()
+	ids := &fixedIDs{traceID: trace.TraceID{0: 0xab, 15: 0xcd}}
+	res := resource.NewSchemaless(attribute.String("service.name", "custom-resource"))
+
+	client, err := New(ctx,
+		WithConfig(DevelopmentConfig()),
+		WithServiceName("orders"),
+		WithProjectID("test-project"),
+		WithLogLevel(logger.LevelWarn),
+		WithLogHandler(slog.NewJSONHandler(&buf, nil)),
+		WithSpanExporter(exporter),
+		WithSampler(sdktrace.AlwaysSample()),
+		WithResource(res),
+		WithPropagator(propagation.TraceContext{}),
+		WithIDGenerator(ids),
+		WithIsolation(),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	c := client.Config()
+	if c.ServiceName != "orders" || c.Environment != "development" || c.LogLevel != logger.LevelWarn {
+		t.Errorf("config = %q %q %v, want options applied over the preset", c.ServiceName, c.Environment, c.LogLevel)
+	}
+	if !c.EnableTracing || !c.Isolated {
+		t.Errorf("tracing %v, isolated %v, want both", c.EnableTracing, c.Isolated)
+	}
+
+	handler := client.HTTPHandler(func(w http.ResponseWriter, r *http.Request) {
+		client.Logger().WarnContext(r.Context(), "slow query")
+	}, "GET /orders")
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	defer client.Shutdown(ctx)
+	flushSpans(t, client)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.SpanContext.TraceID() != ids.traceID {
+		t.Errorf("trace ID = %s, want %s from the ID generator", span.SpanContext.TraceID(), ids.traceID)
+	}
+	if !span.Resource.Equal(res) {
+		t.Errorf("resource = %v, want the configured resource", span.Resource)
+	}
+	if !strings.Contains(buf.String(), "slow query") || !strings.Contains(buf.String(), ids.traceID.String()) {
+		t.Errorf("log = %q, want the handler log correlated to the trace", buf.String())
+	}
+}
+
+func TestNewPropagator(t *testing.T) {
+	ctx := context.Background()
+	exporter := tracetest.NewInMemoryExporter()
+
+	client, err := New(ctx,
+		WithServiceName("orders"),
+		WithProjectID("test-project"),
+		WithSpanExporter(exporter),
+		WithSampler(sdktrace.AlwaysSample()),
+		WithPropagator(propagation.TraceContext{}),
+		WithIsolation(),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	handler := client.OTelHTTP("op")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	defer client.Shutdown(ctx)
+	flushSpans(t, client)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Parent.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("spans = %v, want one child of the incoming trace", spans)
+	}
+}
+
+func TestNewWithBaggage(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	exporter := tracetest.NewInMemoryExporter()
+
+	client, err := New(ctx,
+		WithServiceName("orders"),
+		WithProjectID("test-project"),
+		WithLogHandler(slog.NewJSONHandler(&buf, nil)),
+		WithSpanExporter(exporter),
+		WithSampler(sdktrace.AlwaysSample()),
+		WithBaggage(telemetry.BaggageConfig{Keys: []string{"tenant.id"}}),
+		WithIsolation(),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Shutdown(ctx)
+	buf.Reset()
+
+	handler := client.HTTPHandler(func(w http.ResponseWriter, r *http.Request) {
+		_, span := client.Telemetry().Tracer().Start(r.Context(), "child")
+		span.End()
+	}, "GET /orders")
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("baggage", "tenant.id=acme,role=admin")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	flushSpans(t, client)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	for _, span := range spans {
+		found := false
+		for _, a := range span.Attributes {
+			if a.Key == "role" {
+				t.Errorf("span %s has non-allowlisted member role", span.Name)
+			}
+			found = found || a == attribute.String("tenant.id", "acme")
+		}
+		if !found {
+			t.Errorf("span %s missing tenant.id", span.Name)
+		}
+	}
+	if log := buf.String(); !strings.Contains(log, `"tenant.id":"acme"`) || strings.Contains(log, "admin") {
+		t.Errorf("log = %q, want tenant.id only", log)
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	if _, err := New(context.Background(), WithTracing(telemetry.ExporterStdout, 1.5)); err == nil {
+		t.Error("New() expected error for invalid options")
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+)
+
+// DevelopmentConfig returns a Config for local development: pretty console
+// logs at debug level and every trace printed to stdout. Set ServiceName and
+// ProjectID, and override any other field, before calling NewClient.
+func DevelopmentConfig() Config {
+	return Config{
+		Environment:   "development",
+		EnableConsole: true,
+		PrettyLog:     true,
+		LogLevel:      logger.LevelDebug,
+		EnableTracing: true,
+		TraceRatio:    1.0,
+		TraceExporter: telemetry.ExporterStdout,
+	}
+}
+
+// StagingConfig returns a Config for pre-production: GCP structured logs at
+// debug level with error reporting and redaction, and half of new traces
+// sampled to Cloud Trace.
+func StagingConfig() Config {
+	return Config{
+		Environment:          "staging",
+		EnableGCP:            true,
+		LogLevel:             logger.LevelDebug,
+		EnableErrorReporting: true,
+		Redact:               logger.DefaultRedactConfig(),
+		EnableTracing:        true,
+		TraceRatio:           0.5,
+		TraceExporter:        telemetry.ExporterGCP,
+		ParentBasedSampling:  true,
+	}
+}
+
+// ProductionConfig returns a Config for production: GCP structured logs at
+// info level with error reporting and redaction, and parent-based sampling
+// of 10% of new traces to Cloud Trace.
+func ProductionConfig() Config {
+	return Config{
+		Environment:          "production",
+		EnableGCP:            true,
+		LogLevel:             logger.LevelInfo,
+		EnableErrorReporting: true,
+		Redact:               logger.DefaultRedactConfig(),
+		EnableTracing:        true,
+		TraceRatio:           0.1,
+		TraceExporter:        telemetry.ExporterGCP,
+		ParentBasedSampling:  true,
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+)
+
+func TestPresets(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		environment string
+		console     bool
+		gcp         bool
+		level       logger.Level
+		ratio       float64
+		exporter    telemetry.Exporter
+		parentBased bool
+		redact      bool
+	}{
+		{"development", DevelopmentConfig(), "development", true, false, logger.LevelDebug, 1.0, telemetry.ExporterStdout, false, false},
+		{"staging", StagingConfig(), "staging", false, true, logger.LevelDebug, 0.5, telemetry.ExporterGCP, true, true},
+		{"production", ProductionConfig(), "production", false, true, logger.LevelInfo, 0.1, telemetry.ExporterGCP, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+			if c.Environment != tt.environment {
+				t.Errorf("Environment = %q, want %q", c.Environment, tt.environment)
+			}
+			if c.EnableConsole != tt.console || c.EnableGCP != tt.gcp {
+				t.Errorf("sinks = console %v, gcp %v", c.EnableConsole, c.EnableGCP)
+			}
+			if c.LogLevel != tt.level {
+				t.Errorf("LogLevel = %v, want %v", c.LogLevel, tt.level)
+			}
+			if !c.EnableTracing || c.TraceRatio != tt.ratio {
+				t.Errorf("tracing = %v %v, want true %v", c.EnableTracing, c.TraceRatio, tt.ratio)
+			}
+			if c.TraceExporter != tt.exporter || c.ParentBasedSampling != tt.parentBased {
+				t.Errorf("exporter = %q parent-based %v", c.TraceExporter, c.ParentBasedSampling)
+			}
+			if (c.Redact != nil) != tt.redact {
+				t.Errorf("Redact = %v, want enabled %v", c.Redact, tt.redact)
+			}
+
+			// Presets only need the service identity to validate
+			c.ServiceName = "test-service"
+			c.ProjectID = "test-project"
+			c.SetDefaults()
+			if err := c.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPresetOverrides(t *testing.T) {
+	c := ProductionConfig()
+	c.LogLevel = logger.LevelWarn
+	c.Redact.Keys = append(c.Redact.Keys, "ssn")
+
+	if c.LogLevel != logger.LevelWarn {
+		t.Errorf("LogLevel = %v, want override", c.LogLevel)
+	}
+	// Each call returns fresh values, so overrides do not leak between configs
+	for _, key := range ProductionConfig().Redact.Keys {
+		if key == "ssn" {
+			t.Error("override leaked into a new ProductionConfig")
+		}
+	}
+}
+
+func TestDevelopmentConfigClient(t *testing.T) {
+	ctx := context.Background()
+
+	config := DevelopmentConfig()
+	config.ServiceName = "test-service"
+	config.ProjectID = "test-project"
+
+	client, err := NewClient(ctx, config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Shutdown(ctx)
+
+	if client.Telemetry() == nil {
+		t.Error("Expected telemetry provider with the stdout exporter")
+	}
+}
@@ -0,0 +1,34 @@
+package pubsub
+
+import "strings"
+
+// attributePrefix namespaces trace context keys in message attributes, as the
+// Cloud Pub/Sub client libraries do
+const attributePrefix = "googclient_"
+
+// AttributesCarrier adapts message attributes to propagation.TextMapCarrier.
+// Set writes googclient_-prefixed keys such as googclient_traceparent; Get
+// reads those and falls back to unprefixed keys written by other publishers.
+type AttributesCarrier map[string]string
+
+// Get returns the value of key
+func (c AttributesCarrier) Get(key string) string {
+	if v, ok := c[attributePrefix+key]; ok {
+		return v
+	}
+	return c[key]
+}
+
+// Set stores value under the prefixed key
+func (c AttributesCarrier) Set(key, value string) {
+	c[attributePrefix+key] = value
+}
+
+// Keys returns the propagation keys present, without the prefix
+func (c AttributesCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, strings.TrimPrefix(k, attributePrefix))
+	}
+	return keys
+}
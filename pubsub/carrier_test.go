@@ -0,0 +1,27 @@
+package pubsub
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAttributesCarrier(t *testing.T) {
+	c := AttributesCarrier{"traceparent": "plain", "order": "42"}
+	if got := c.Get("traceparent"); got != "plain" {
+		t.Errorf("Get() unprefixed = %q", got)
+	}
+
+	c.Set("traceparent", "prefixed")
+	if c["googclient_traceparent"] != "prefixed" {
+		t.Errorf("Set() wrote %v, want a googclient_ key", c)
+	}
+	if got := c.Get("traceparent"); got != "prefixed" {
+		t.Errorf("Get() = %q, want the prefixed value first", got)
+	}
+
+	keys := c.Keys()
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"order", "traceparent", "traceparent"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
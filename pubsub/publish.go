@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Inject writes the trace context and baggage of ctx into message attributes
+// under googclient_ keys, which PushHandler and the Pub/Sub client libraries
+// read. It returns attributes, or a new map when attributes is nil.
+func Inject(ctx context.Context, attributes map[string]string) map[string]string {
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, AttributesCarrier(attributes))
+	return attributes
+}
+
+// StartPublish starts a "send <topic>" producer span for publishing one
+// message and injects its context into attributes. It returns attributes, or
+// a new map when attributes is nil, to publish with the message. End the span
+// once the publish result is known.
+func StartPublish(ctx context.Context, topic string, attributes map[string]string) (context.Context, trace.Span, map[string]string) {
+	ctx, span := telemetry.StartProducerSpan(ctx, "send "+shortName(topic), telemetry.SpanOptions{
+		Attributes: append(telemetry.TracePubSubMessage("send", topic, ""),
+			semconv.MessagingOperationTypeSend,
+		),
+	})
+	return ctx, span, Inject(ctx, attributes)
+}
+
+// Batch holds the spans of a batch publish
+type Batch struct {
+	Span     trace.Span   // The "send" span covering the publish call
+	Messages []trace.Span // One "create" span per message, linked to Span
+}
+
+// StartBatchPublish starts a "send <topic>" span for publishing several
+// messages and a "create <topic>" span per message, each linked to the batch
+// span and injected into that message's attributes. Nil attribute maps are
+// replaced with new ones. Call End with the publish result.
+func StartBatchPublish(ctx context.Context, topic string, attributes []map[string]string) (context.Context, *Batch) {
+	name := shortName(topic)
+	batchCtx, batchSpan := telemetry.StartProducerSpan(ctx, "send "+name, telemetry.SpanOptions{
+		Attributes: append(telemetry.TracePubSubMessage("send", topic, ""),
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingBatchMessageCount(len(attributes)),
+		),
+	})
+
+	b := &Batch{Span: batchSpan, Messages: make([]trace.Span, len(attributes))}
+	link := trace.Link{SpanContext: batchSpan.SpanContext()}
+	for i := range attributes {
+		msgCtx, span := telemetry.StartProducerSpan(ctx, "create "+name, telemetry.SpanOptions{
+			Attributes: append(telemetry.TracePubSubMessage("create", topic, ""),
+				semconv.MessagingOperationTypeCreate,
+			),
+			Links: []trace.Link{link},
+		})
+		attributes[i] = Inject(msgCtx, attributes[i])
+		b.Messages[i] = span
+	}
+	return batchCtx, b
+}
+
+// SetMessageID records the server-assigned ID of message i
+func (b *Batch) SetMessageID(i int, id string) {
+	b.Messages[i].SetAttributes(semconv.MessagingMessageID(id))
+}
+
+// End records err, if any, on every span and ends them
+func (b *Batch) End(err error) {
+	for _, span := range b.Messages {
+		endPublish(span, err)
+	}
+	endPublish(b.Span, err)
+}
+
+// endPublish sets the span status from the publish result and ends it
+func endPublish(span trace.Span, err error) {
+	if err != nil {
+		telemetry.RecordError(span, err, "publish failed")
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
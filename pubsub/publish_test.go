@@ -0,0 +1,109 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartPublish(t *testing.T) {
+	rec := useRecorder(t)
+
+	_, span, attrs := StartPublish(context.Background(), "projects/my-project/topics/orders", map[string]string{"type": "order.created"})
+	span.End()
+
+	if _, ok := attrs["googclient_traceparent"]; !ok {
+		t.Fatalf("attributes = %v, want googclient_traceparent", attrs)
+	}
+	if attrs["type"] != "order.created" {
+		t.Error("existing attribute was changed")
+	}
+
+	s := rec.Ended()[0]
+	if s.Name() != "send orders" || s.SpanKind() != trace.SpanKindProducer {
+		t.Errorf("span = %q kind %v", s.Name(), s.SpanKind())
+	}
+	a := spanAttrs(s)
+	if a["messaging.destination"].AsString() != "projects/my-project/topics/orders" ||
+		a["messaging.operation.type"].AsString() != "send" || a["messaging.operation"].AsString() != "send" {
+		t.Errorf("span attributes = %v", a)
+	}
+
+	// The consumer continues the publisher's trace
+	parent := otel.GetTextMapPropagator().Extract(context.Background(), AttributesCarrier(attrs))
+	if sc := trace.SpanContextFromContext(parent); sc.SpanID() != s.SpanContext().SpanID() {
+		t.Errorf("extracted span = %v, want the producer span", sc.SpanID())
+	}
+}
+
+func TestStartPublishNilAttributes(t *testing.T) {
+	useRecorder(t)
+
+	_, span, attrs := StartPublish(context.Background(), "orders", nil)
+	span.End()
+	if _, ok := attrs["googclient_traceparent"]; !ok {
+		t.Errorf("attributes = %v, want a new map with googclient_traceparent", attrs)
+	}
+}
+
+func TestStartBatchPublish(t *testing.T) {
+	rec := useRecorder(t)
+
+	attrs := []map[string]string{{"n": "1"}, nil, {"n": "3"}}
+	_, batch := StartBatchPublish(context.Background(), "projects/my-project/topics/orders", attrs)
+	batch.SetMessageID(0, "m-1")
+	batch.End(nil)
+
+	spans := rec.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("spans = %d, want 3 messages and the batch", len(spans))
+	}
+	batchSpan := spans[3]
+	if batchSpan.Name() != "send orders" || spanAttrs(batchSpan)["messaging.batch.message_count"].AsInt64() != 3 {
+		t.Errorf("batch span = %q %v", batchSpan.Name(), spanAttrs(batchSpan))
+	}
+
+	seen := map[string]bool{}
+	for i, s := range spans[:3] {
+		if op := spanAttrs(s)["messaging.operation"].AsString(); op != "create" {
+			t.Errorf("message span %d messaging.operation = %q, want create", i, op)
+		}
+		if s.Name() != "create orders" || s.Status().Code != codes.Ok {
+			t.Errorf("message span %d = %q status %v", i, s.Name(), s.Status())
+		}
+		if links := s.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != batchSpan.SpanContext().SpanID() {
+			t.Errorf("message span %d links = %v, want the batch span", i, links)
+		}
+		if attrs[i] == nil {
+			t.Fatalf("attributes[%d] is nil", i)
+		}
+		sc := trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(), AttributesCarrier(attrs[i])))
+		if sc.SpanID() != s.SpanContext().SpanID() {
+			t.Errorf("attributes[%d] carry span %v, want %v", i, sc.SpanID(), s.SpanContext().SpanID())
+		}
+		seen[sc.SpanID().String()] = true
+	}
+	if len(seen) != 3 {
+		t.Error("messages share a span context")
+	}
+	if spanAttrs(spans[0])["messaging.message.id"].AsString() != "m-1" {
+		t.Error("message ID not recorded")
+	}
+}
+
+func TestBatchEndError(t *testing.T) {
+	rec := useRecorder(t)
+
+	_, batch := StartBatchPublish(context.Background(), "orders", make([]map[string]string, 2))
+	batch.End(errors.New("topic not found"))
+
+	for _, s := range rec.Ended() {
+		if s.Status().Code != codes.Error {
+			t.Errorf("span %q status = %v, want error", s.Name(), s.Status())
+		}
+	}
+}
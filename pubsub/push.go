@@ -0,0 +1,222 @@
+// Package pubsub traces Cloud Pub/Sub messages end to end without depending
+// on the Pub/Sub client library.
+//
+// PushHandler serves a push subscription endpoint:
+//
+//	http.Handle("/pubsub/orders", pubsub.PushHandler(func(ctx context.Context, m pubsub.Message) error {
+//		return process(ctx, m.Data)
+//	}))
+//
+// Publishers inject the trace context into message attributes so the
+// consumer's span continues the trace:
+//
+//	ctx, span, attrs := pubsub.StartPublish(ctx, "projects/my-project/topics/orders",
+//		map[string]string{"type": "order.created"})
+//	_, err := topic.Publish(ctx, &gpubsub.Message{Data: data, Attributes: attrs}).Get(ctx)
+//	telemetry.RecordError(span, err, "publish failed")
+//	span.End()
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirhco/go-gcp-middleware/auth"
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxPushBody bounds the push request read; Pub/Sub messages are at most 10 MB
+// before base64 encoding
+const maxPushBody = 16 << 20
+
+// Message is a Pub/Sub message delivered to a push endpoint
+type Message struct {
+	ID              string
+	Data            []byte
+	Attributes      map[string]string
+	OrderingKey     string
+	PublishTime     time.Time
+	DeliveryAttempt int    // Set only when the subscription has a dead-letter policy
+	Subscription    string // Full name, projects/PROJECT/subscriptions/NAME
+}
+
+// Handler processes a pushed message. A nil error acks the message; any
+// other error nacks it and Pub/Sub redelivers it.
+type Handler func(ctx context.Context, m Message) error
+
+// pushEnvelope is the JSON body of a push request
+type pushEnvelope struct {
+	Message struct {
+		Data        []byte            `json:"data"`
+		Attributes  map[string]string `json:"attributes"`
+		MessageID   string            `json:"messageId"`
+		PublishTime time.Time         `json:"publishTime"`
+		OrderingKey string            `json:"orderingKey"`
+	} `json:"message"`
+	Subscription    string `json:"subscription"`
+	DeliveryAttempt int    `json:"deliveryAttempt"`
+}
+
+// pushOptions configure PushHandler
+type pushOptions struct {
+	logger        *logger.Logger
+	audiences     []string
+	allowedEmails []string
+	authOpts      []auth.Option
+	verify        bool
+}
+
+// PushOption configures PushHandler
+type PushOption func(*pushOptions)
+
+// WithOIDC verifies the OIDC token Pub/Sub sends for an authenticated push
+// subscription, as auth.GoogleOIDC does
+func WithOIDC(audiences, allowedEmails []string, opts ...auth.Option) PushOption {
+	return func(o *pushOptions) {
+		o.verify = true
+		o.audiences = audiences
+		o.allowedEmails = allowedEmails
+		o.authOpts = opts
+	}
+}
+
+// WithLogger logs failures to l instead of the global logger
+func WithLogger(l *logger.Logger) PushOption {
+	return func(o *pushOptions) {
+		o.logger = l
+	}
+}
+
+// log returns the configured logger or the global one
+func (o pushOptions) log() *logger.Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return logger.Global()
+}
+
+// PushHandler returns a handler for a push subscription endpoint. It decodes
+// the envelope, continues the publisher's trace from the message attributes
+// in a consumer span, and calls fn. Pub/Sub treats the 204 returned when fn
+// succeeds as an ack and the 500 returned when it fails as a nack. Malformed
+// requests get 400, which Pub/Sub also redelivers, so pair the subscription
+// with a dead-letter topic.
+func PushHandler(fn Handler, opts ...PushOption) http.Handler {
+	var o pushOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.serve(w, r, fn)
+	})
+	if o.verify {
+		authOpts := append([]auth.Option{auth.WithLogger(o.log())}, o.authOpts...)
+		h = auth.GoogleOIDC(o.audiences, o.allowedEmails, authOpts...)(h)
+	}
+	return h
+}
+
+// serve handles a single push request
+func (o pushOptions) serve(w http.ResponseWriter, r *http.Request, fn Handler) {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	m, err := decodePush(r)
+	if err != nil {
+		o.log().WarnContext(ctx, "Invalid Pub/Sub push request", "error", err)
+		http.Error(w, "invalid push request", http.StatusBadRequest)
+		return
+	}
+
+	ctx, span := startProcessSpan(ctx, m)
+	defer span.End()
+	ctx = logger.WithAttrs(ctx,
+		"messaging.message.id", m.ID,
+		"messaging.destination.subscription.name", m.Subscription,
+	)
+
+	if err := fn(ctx, m); err != nil {
+		telemetry.RecordError(span, err, "message processing failed")
+		o.log().ErrorContext(ctx, "Pub/Sub message processing failed",
+			"error", err,
+			"delivery_attempt", m.DeliveryAttempt,
+		)
+		http.Error(w, "message processing failed", http.StatusInternalServerError)
+		return
+	}
+	span.SetStatus(codes.Ok, "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodePush reads the push envelope from the request body
+func decodePush(r *http.Request) (Message, error) {
+	var env pushEnvelope
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxPushBody)).Decode(&env); err != nil {
+		return Message{}, fmt.Errorf("failed to decode push envelope: %w", err)
+	}
+	if env.Message.MessageID == "" {
+		return Message{}, fmt.Errorf("push envelope has no message ID")
+	}
+	return Message{
+		ID:              env.Message.MessageID,
+		Data:            env.Message.Data,
+		Attributes:      env.Message.Attributes,
+		OrderingKey:     env.Message.OrderingKey,
+		PublishTime:     env.Message.PublishTime,
+		DeliveryAttempt: env.DeliveryAttempt,
+		Subscription:    env.Subscription,
+	}, nil
+}
+
+// startProcessSpan starts the consumer span for m. The publisher's context
+// from the attributes becomes the parent so the trace continues end to end;
+// the push request's own span, if any, is linked.
+func startProcessSpan(ctx context.Context, m Message) (context.Context, trace.Span) {
+	subscription := shortName(m.Subscription)
+	attrs := append(telemetry.TracePubSubMessage("process", "", subscription),
+		semconv.MessagingOperationTypeProcess,
+		semconv.MessagingMessageID(m.ID),
+		semconv.MessagingMessageBodySize(len(m.Data)),
+		semconv.MessagingDestinationSubscriptionName(m.Subscription),
+	)
+	if m.OrderingKey != "" {
+		attrs = append(attrs, semconv.MessagingGCPPubSubMessageOrderingKey(m.OrderingKey))
+	}
+	if m.DeliveryAttempt > 0 {
+		attrs = append(attrs, semconv.MessagingGCPPubSubMessageDeliveryAttempt(m.DeliveryAttempt))
+	}
+	if !m.PublishTime.IsZero() {
+		attrs = append(attrs, attribute.Int64("messaging.gcp_pubsub.message.publish_time_ms", m.PublishTime.UnixMilli()))
+	}
+
+	opts := telemetry.SpanOptions{Attributes: attrs}
+	push := trace.SpanContextFromContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, AttributesCarrier(m.Attributes))
+	if push.IsValid() && !push.Equal(trace.SpanContextFromContext(ctx)) {
+		opts.Links = []trace.Link{{SpanContext: push}}
+	}
+	return telemetry.StartConsumerSpan(ctx, "process "+subscription, opts)
+}
+
+// shortName returns the last path segment of a resource name
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,232 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirhco/go-gcp-middleware/auth"
+	"github.com/sirhco/go-gcp-middleware/logger"
+	"github.com/sirhco/go-gcp-middleware/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useRecorder installs a global tracer provider and propagator for the test
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(telemetry.NewPropagator())
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+		tp.Shutdown(context.Background())
+	})
+	return rec
+}
+
+// newTestLogger returns a logger writing JSON to buf
+func newTestLogger(t *testing.T, buf *bytes.Buffer) *logger.Logger {
+	t.Helper()
+	l, err := logger.NewLogger(context.Background(), logger.Config{
+		ServiceName: "pubsub-test",
+		Handlers:    []slog.Handler{slog.NewJSONHandler(buf, nil)},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	return l
+}
+
+// spanAttrs returns the attributes of s keyed by name
+func spanAttrs(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func pushBody(t *testing.T, attrs map[string]string) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"data":        base64.StdEncoding.EncodeToString([]byte(`{"order":42}`)),
+			"attributes":  attrs,
+			"messageId":   "1234",
+			"publishTime": "2026-01-01T12:00:00.5Z",
+			"orderingKey": "customer-7",
+		},
+		"subscription":    "projects/my-project/subscriptions/orders-push",
+		"deliveryAttempt": 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestPushHandler(t *testing.T) {
+	rec := useRecorder(t)
+	var buf bytes.Buffer
+
+	var got Message
+	var gotCtx context.Context
+	handlerErr := error(nil)
+	handler := PushHandler(func(ctx context.Context, m Message) error {
+		got, gotCtx = m, ctx
+		return handlerErr
+	}, WithLogger(newTestLogger(t, &buf)))
+
+	t.Run("ack", func(t *testing.T) {
+		rec.Reset()
+		req := httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(pushBody(t, map[string]string{
+			"googclient_traceparent": testTraceparent,
+			"type":                   "order.created",
+		})))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want 204", w.Code)
+		}
+		if got.ID != "1234" || string(got.Data) != `{"order":42}` || got.OrderingKey != "customer-7" ||
+			got.DeliveryAttempt != 3 || got.Attributes["type"] != "order.created" ||
+			got.Subscription != "projects/my-project/subscriptions/orders-push" ||
+			!got.PublishTime.Equal(time.Date(2026, 1, 1, 12, 0, 0, 5e8, time.UTC)) {
+			t.Errorf("message = %+v", got)
+		}
+
+		spans := rec.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("spans = %d, want 1", len(spans))
+		}
+		s := spans[0]
+		if s.Name() != "process orders-push" || s.SpanKind() != trace.SpanKindConsumer {
+			t.Errorf("span = %q kind %v", s.Name(), s.SpanKind())
+		}
+		if s.Parent().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || s.Parent().SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("parent = %v, want the publisher's span", s.Parent())
+		}
+		attrs := spanAttrs(s)
+		if attrs["messaging.message.id"].AsString() != "1234" ||
+			attrs["messaging.operation.type"].AsString() != "process" ||
+			attrs["messaging.gcp_pubsub.message.delivery_attempt"].AsInt64() != 3 ||
+			attrs["messaging.gcp_pubsub.message.ordering_key"].AsString() != "customer-7" {
+			t.Errorf("span attributes = %v", attrs)
+		}
+
+		var logged bool
+		for _, a := range logger.AttrsFromContext(gotCtx) {
+			if a.Key == "messaging.message.id" && a.Value.String() == "1234" {
+				logged = true
+			}
+		}
+		if !logged {
+			t.Error("messaging.message.id missing from log attrs")
+		}
+	})
+
+	t.Run("nack", func(t *testing.T) {
+		rec.Reset()
+		buf.Reset()
+		handlerErr = errors.New("database unavailable")
+		defer func() { handlerErr = nil }()
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(pushBody(t, nil))))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d, want 500", w.Code)
+		}
+		if spans := rec.Ended(); len(spans) != 1 || len(spans[0].Events()) == 0 {
+			t.Error("error not recorded on the span")
+		}
+		if !strings.Contains(buf.String(), "database unavailable") {
+			t.Errorf("log = %s, want the handler error", buf.String())
+		}
+	})
+
+	t.Run("links the push request span", func(t *testing.T) {
+		rec.Reset()
+		ctx, push := otel.Tracer("test").Start(context.Background(), "POST /push")
+		req := httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(pushBody(t, map[string]string{
+			"traceparent": testTraceparent,
+		}))).WithContext(ctx)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		push.End()
+
+		s := rec.Ended()[0]
+		if s.Parent().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("parent = %v, want the publisher's span from the unprefixed key", s.Parent())
+		}
+		if links := s.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != push.SpanContext().SpanID() {
+			t.Errorf("links = %v, want the push request span", links)
+		}
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		tests := []struct {
+			method string
+			body   string
+			status int
+		}{
+			{http.MethodGet, "", http.StatusMethodNotAllowed},
+			{http.MethodPost, "not json", http.StatusBadRequest},
+			{http.MethodPost, `{"message":{}}`, http.StatusBadRequest},
+			{http.MethodPost, `{"message":{"messageId":"1","data":"!!"}}`, http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/push", strings.NewReader(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("%s %q: status = %d, want %d", tt.method, tt.body, w.Code, tt.status)
+			}
+		}
+	})
+}
+
+func TestPushHandlerOIDC(t *testing.T) {
+	useRecorder(t)
+	var buf bytes.Buffer
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	handler := PushHandler(func(ctx context.Context, m Message) error {
+		called = true
+		return nil
+	},
+		WithLogger(newTestLogger(t, &buf)),
+		WithOIDC([]string{"https://push.example.com"}, nil, auth.WithKeySource(auth.StaticKeys{"k": &key.PublicKey})),
+	)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(pushBody(t, nil))))
+	if w.Code != http.StatusUnauthorized || called {
+		t.Errorf("status = %d, called = %v, want 401 without a token", w.Code, called)
+	}
+	if !strings.Contains(buf.String(), "missing_token") {
+		t.Errorf("log = %s, want the rejection on the configured logger", buf.String())
+	}
+}
@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// defaultMaxBaggageValueLength bounds the members copied onto spans and logs
+const defaultMaxBaggageValueLength = 256
+
+// BaggageConfig selects the baggage members trusted from callers and copied
+// onto spans and logs, for example tenant and user IDs
+type BaggageConfig struct {
+	Keys           []string // Allowlisted member keys, e.g. "tenant.id"; others are dropped
+	MaxValueLength int      // Members with longer values are dropped (default: 256 bytes)
+}
+
+// SetDefaults applies default values to unset fields
+func (c *BaggageConfig) SetDefaults() {
+	if c.MaxValueLength <= 0 {
+		c.MaxValueLength = defaultMaxBaggageValueLength
+	}
+}
+
+// Filter returns the allowlisted members of b whose values fit the size limit
+func (c BaggageConfig) Filter(b baggage.Baggage) baggage.Baggage {
+	c.SetDefaults()
+
+	var members []baggage.Member
+	for _, m := range b.Members() {
+		if slices.Contains(c.Keys, m.Key()) && len(m.Value()) <= c.MaxValueLength {
+			members = append(members, m)
+		}
+	}
+	filtered, err := baggage.New(members...)
+	if err != nil {
+		// Members of a valid baggage are valid on their own
+		return baggage.Baggage{}
+	}
+	return filtered
+}
+
+// Attributes returns the allowlisted members of the context's baggage as
+// span attributes keyed by member key
+func (c BaggageConfig) Attributes(ctx context.Context) []attribute.KeyValue {
+	members := c.Filter(baggage.FromContext(ctx)).Members()
+	if len(members) == 0 {
+		return nil
+	}
+	attrs := make([]attribute.KeyValue, 0, len(members))
+	for _, m := range members {
+		attrs = append(attrs, attribute.String(m.Key(), m.Value()))
+	}
+	return attrs
+}
+
+// SetBaggage returns ctx with key=value added to its baggage. The propagator
+// sends it to downstream services, including through helpers.TraceHTTPClient.
+func SetBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, fmt.Errorf("invalid baggage member %q: %w", key, err)
+	}
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to set baggage member %q: %w", key, err)
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// GetBaggage returns the value of a baggage member in the context, or "" if absent
+func GetBaggage(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// baggageSpanProcessor copies allowlisted baggage members onto every span
+type baggageSpanProcessor struct {
+	config BaggageConfig
+}
+
+// NewBaggageSpanProcessor returns a processor that adds the allowlisted
+// baggage members of each span's parent context to the span as attributes
+func NewBaggageSpanProcessor(config BaggageConfig) sdktrace.SpanProcessor {
+	config.SetDefaults()
+	return &baggageSpanProcessor{config: config}
+}
+
+func (p *baggageSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if attrs := p.config.Attributes(parent); len(attrs) > 0 {
+		s.SetAttributes(attrs...)
+	}
+}
+
+func (p *baggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *baggageSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *baggageSpanProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// mustBaggage parses a W3C baggage header value
+func mustBaggage(t *testing.T, s string) baggage.Baggage {
+	t.Helper()
+	b, err := baggage.Parse(s)
+	if err != nil {
+		t.Fatalf("baggage.Parse(%q) error = %v", s, err)
+	}
+	return b
+}
+
+func TestBaggageConfigFilter(t *testing.T) {
+	config := BaggageConfig{Keys: []string{"tenant.id", "user.id"}, MaxValueLength: 8}
+	b := mustBaggage(t, "tenant.id=acme,user.id=toolongvalue,role=admin")
+
+	got := config.Filter(b)
+	if got.Len() != 1 || got.Member("tenant.id").Value() != "acme" {
+		t.Errorf("Filter() = %q, want only tenant.id=acme", got.String())
+	}
+}
+
+func TestBaggageConfigAttributes(t *testing.T) {
+	config := BaggageConfig{Keys: []string{"tenant.id"}}
+	ctx := baggage.ContextWithBaggage(context.Background(), mustBaggage(t, "tenant.id=acme,role=admin"))
+
+	attrs := config.Attributes(ctx)
+	if len(attrs) != 1 || attrs[0] != attribute.String("tenant.id", "acme") {
+		t.Errorf("Attributes() = %v", attrs)
+	}
+	if attrs := config.Attributes(context.Background()); attrs != nil {
+		t.Errorf("Attributes() without baggage = %v, want nil", attrs)
+	}
+}
+
+func TestSetBaggage(t *testing.T) {
+	ctx, err := SetBaggage(context.Background(), "tenant.id", "acme corp")
+	if err != nil {
+		t.Fatalf("SetBaggage() error = %v", err)
+	}
+	ctx, err = SetBaggage(ctx, "user.id", "42")
+	if err != nil {
+		t.Fatalf("SetBaggage() error = %v", err)
+	}
+	if got := GetBaggage(ctx, "tenant.id"); got != "acme corp" {
+		t.Errorf("GetBaggage(tenant.id) = %q", got)
+	}
+	if got := GetBaggage(ctx, "user.id"); got != "42" {
+		t.Errorf("GetBaggage(user.id) = %q", got)
+	}
+
+	if _, err := SetBaggage(ctx, "", "v"); err == nil || !strings.Contains(err.Error(), "invalid baggage member") {
+		t.Errorf("SetBaggage() with invalid key error = %v", err)
+	}
+}
+
+func TestBaggageSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewBaggageSpanProcessor(BaggageConfig{Keys: []string{"tenant.id"}})),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	tracer := tp.Tracer("test")
+
+	ctx, err := SetBaggage(context.Background(), "tenant.id", "acme")
+	if err != nil {
+		t.Fatalf("SetBaggage() error = %v", err)
+	}
+	ctx, _ = SetBaggage(ctx, "role", "admin")
+
+	ctx, parent := tracer.Start(ctx, "parent")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	parent.End()
+
+	for _, span := range recorder.Ended() {
+		var tenant string
+		for _, a := range span.Attributes() {
+			if a.Key == "role" {
+				t.Errorf("span %s has non-allowlisted member role", span.Name())
+			}
+			if a.Key == "tenant.id" {
+				tenant = a.Value.AsString()
+			}
+		}
+		if tenant != "acme" {
+			t.Errorf("span %s tenant.id = %q, want acme", span.Name(), tenant)
+		}
+	}
+}
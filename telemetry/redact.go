@@ -0,0 +1,88 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// AttributeRedactor scrubs sensitive values from span attributes.
+// logger.Redactor implements it so logs and spans share the same rules.
+type AttributeRedactor interface {
+	RedactAttributes(attrs []attribute.KeyValue) []attribute.KeyValue
+}
+
+// statusDescriptionKey presents a span's status description to the redactor,
+// so value patterns apply to error text set with SetStatus
+const statusDescriptionKey = "otel.status_description"
+
+// redactingSpanProcessor scrubs span, event and link attributes and the status
+// description before handing ended spans to the next processor for export
+type redactingSpanProcessor struct {
+	next     sdktrace.SpanProcessor
+	redactor AttributeRedactor
+}
+
+// NewRedactingSpanProcessor wraps next so every span it receives has its
+// attributes, event and link attributes, and status description redacted
+func NewRedactingSpanProcessor(next sdktrace.SpanProcessor, redactor AttributeRedactor) sdktrace.SpanProcessor {
+	return &redactingSpanProcessor{next: next, redactor: redactor}
+}
+
+func (p *redactingSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *redactingSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.next.OnEnd(&redactedSpan{ReadOnlySpan: s, redactor: p.redactor})
+}
+
+func (p *redactingSpanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *redactingSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// redactedSpan presents a redacted view of an ended span
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	redactor AttributeRedactor
+}
+
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return s.redactor.RedactAttributes(s.ReadOnlySpan.Attributes())
+}
+
+func (s *redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	redacted := make([]sdktrace.Event, len(events))
+	for i, event := range events {
+		event.Attributes = s.redactor.RedactAttributes(event.Attributes)
+		redacted[i] = event
+	}
+	return redacted
+}
+
+func (s *redactedSpan) Links() []sdktrace.Link {
+	links := s.ReadOnlySpan.Links()
+	redacted := make([]sdktrace.Link, len(links))
+	for i, link := range links {
+		link.Attributes = s.redactor.RedactAttributes(link.Attributes)
+		redacted[i] = link
+	}
+	return redacted
+}
+
+func (s *redactedSpan) Status() sdktrace.Status {
+	status := s.ReadOnlySpan.Status()
+	if status.Description != "" {
+		attrs := s.redactor.RedactAttributes([]attribute.KeyValue{attribute.String(statusDescriptionKey, status.Description)})
+		if len(attrs) == 1 {
+			status.Description = attrs[0].Value.Emit()
+		}
+	}
+	return status
+}
@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maskRedactor masks every string attribute value containing "secret"
+type maskRedactor struct{}
+
+func (maskRedactor) RedactAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, len(attrs))
+	for i, kv := range attrs {
+		if strings.Contains(kv.Value.Emit(), "secret") {
+			kv = attribute.String(string(kv.Key), "[REDACTED]")
+		}
+		out[i] = kv
+	}
+	return out
+}
+
+func TestRedactingSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewRedactingSpanProcessor(recorder, maskRedactor{})),
+	)
+	defer tp.Shutdown(context.Background())
+
+	link := trace.Link{
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}}),
+		Attributes:  []attribute.KeyValue{attribute.String("messaging.message.body", "secret payload")},
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "op", trace.WithLinks(link))
+	span.SetStatus(codes.Error, "login failed for secret token")
+	span.SetAttributes(attribute.String("db.statement", "select secret"), attribute.Int("rows", 1))
+	span.AddEvent("log", trace.WithAttributes(attribute.String("log.message", "secret value")))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 ended span, got %d", len(spans))
+	}
+
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == "db.statement" && kv.Value.AsString() != "[REDACTED]" {
+			t.Errorf("db.statement = %q, want [REDACTED]", kv.Value.AsString())
+		}
+		if kv.Key == "rows" && kv.Value.AsInt64() != 1 {
+			t.Errorf("rows = %d, want 1", kv.Value.AsInt64())
+		}
+	}
+
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Attributes[0].Value.AsString() != "[REDACTED]" {
+		t.Errorf("event attributes were not redacted: %v", events)
+	}
+
+	links := spans[0].Links()
+	if len(links) != 1 || links[0].Attributes[0].Value.AsString() != "[REDACTED]" {
+		t.Errorf("link attributes were not redacted: %v", links)
+	}
+
+	if status := spans[0].Status(); status.Code != codes.Error || status.Description != "[REDACTED]" {
+		t.Errorf("status = %+v, want redacted description", status)
+	}
+}
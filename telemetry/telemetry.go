@@ -4,17 +4,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 
 	gcptrace "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	"go.opentelemetry.io/contrib/detectors/gcp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Exporter selects where spans are sent
+type Exporter string
+
+const (
+	ExporterGCP    Exporter = "gcp"    // Google Cloud Trace (default)
+	ExporterStdout Exporter = "stdout" // Pretty-printed JSON on stdout, for local development
 )
 
 // Config holds telemetry configuration
@@ -28,6 +39,13 @@ type Config struct {
 	TraceRatio     float64
 	EnableDebug    bool
 
+	// Exporter selects the span destination (default: ExporterGCP)
+	Exporter Exporter
+
+	// ParentBased follows the sampling decision of the incoming trace
+	// context and applies TraceRatio only to new traces
+	ParentBased bool
+
 	// Export configuration
 	ExportTimeout time.Duration
 	BatchTimeout  time.Duration
@@ -36,6 +54,23 @@ type Config struct {
 
 	// Custom attributes
 	Attributes map[string]string
+
+	// Redactor scrubs span attributes before export. Nil disables redaction.
+	Redactor AttributeRedactor
+
+	// Isolated keeps the tracer provider and propagator out of the
+	// OpenTelemetry globals, so several providers can coexist
+	Isolated bool
+
+	// Baggage copies allowlisted baggage members onto every span (nil disables)
+	Baggage *BaggageConfig
+
+	// Overrides for advanced setups; nil keeps the built-in behavior
+	SpanExporter sdktrace.SpanExporter         // Replaces the exporter chosen by Exporter
+	Sampler      sdktrace.Sampler              // Replaces the TraceRatio sampler
+	Resource     *resource.Resource            // Replaces the detected service and GCP resource
+	Propagator   propagation.TextMapPropagator // Replaces the trace context and baggage propagator
+	IDGenerator  sdktrace.IDGenerator          // Replaces the random trace and span ID generator
 }
 
 // SetDefaults sets reasonable defaults for the configuration
@@ -55,6 +90,9 @@ func (c *Config) SetDefaults() {
 	if c.TraceRatio == 0 {
 		c.TraceRatio = 0.1 // Default to 10% sampling
 	}
+	if c.Exporter == "" {
+		c.Exporter = ExporterGCP
+	}
 	if c.ProjectID == "" {
 		c.ProjectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
 		if c.ProjectID == "" {
@@ -83,7 +121,12 @@ func (c *Config) Validate() error {
 	if c.ServiceName == "" {
 		return fmt.Errorf("ServiceName is required")
 	}
-	if c.EnableTracing && c.ProjectID == "" {
+	switch c.Exporter {
+	case "", ExporterGCP, ExporterStdout:
+	default:
+		return fmt.Errorf("unknown Exporter %q", c.Exporter)
+	}
+	if c.EnableTracing && c.Exporter != ExporterStdout && c.SpanExporter == nil && c.ProjectID == "" {
 		return fmt.Errorf("ProjectID is required when tracing is enabled")
 	}
 	if c.TraceRatio < 0 || c.TraceRatio > 1 {
@@ -123,7 +166,9 @@ func NewProvider(ctx context.Context, config Config) (*Provider, error) {
 	}
 
 	// Configure propagation for distributed tracing
-	provider.setupPropagation()
+	if !config.Isolated {
+		provider.setupPropagation()
+	}
 
 	// Create default tracer
 	provider.tracer = provider.GetTracer(config.ServiceName)
@@ -131,7 +176,7 @@ func NewProvider(ctx context.Context, config Config) (*Provider, error) {
 	return provider, nil
 }
 
-// setupTracing configures Google Cloud Trace
+// setupTracing configures the tracer provider with the selected exporter
 func (p *Provider) setupTracing(ctx context.Context) error {
 	// Create resource with GCP detection
 	res, err := p.createResource(ctx)
@@ -139,16 +184,80 @@ func (p *Provider) setupTracing(ctx context.Context) error {
 		return fmt.Errorf("failed to create resource: %w", err)
 	}
 
+	exporter, err := p.newExporter()
+	if err != nil {
+		return err
+	}
+
+	// Batch processing for Cloud Trace; the stdout exporter writes each span
+	// as it ends so local output is immediate. Optionally behind redaction.
+	var processor sdktrace.SpanProcessor
+	if p.config.Exporter == ExporterStdout {
+		processor = sdktrace.NewSimpleSpanProcessor(exporter)
+	} else {
+		processor = sdktrace.NewBatchSpanProcessor(exporter,
+			sdktrace.WithBatchTimeout(p.config.BatchTimeout),
+			sdktrace.WithMaxExportBatchSize(p.config.MaxBatchSize),
+			sdktrace.WithMaxQueueSize(p.config.MaxQueueSize),
+		)
+	}
+	if p.config.Redactor != nil {
+		processor = NewRedactingSpanProcessor(processor, p.config.Redactor)
+	}
+
+	// Create tracer provider
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(p.sampler()),
+	}
+	if p.config.Baggage != nil {
+		opts = append(opts, sdktrace.WithSpanProcessor(NewBaggageSpanProcessor(*p.config.Baggage)))
+	}
+	opts = append(opts, sdktrace.WithSpanProcessor(processor))
+	if p.config.IDGenerator != nil {
+		opts = append(opts, sdktrace.WithIDGenerator(p.config.IDGenerator))
+	}
+	p.tracerProvider = sdktrace.NewTracerProvider(opts...)
+
+	// Set as global tracer provider
+	if !p.config.Isolated {
+		otel.SetTracerProvider(p.tracerProvider)
+	}
+
+	return nil
+}
+
+// newExporter returns the configured SpanExporter or creates the one selected by Exporter
+func (p *Provider) newExporter() (sdktrace.SpanExporter, error) {
+	if p.config.SpanExporter != nil {
+		return p.config.SpanExporter, nil
+	}
+	if p.config.Exporter == ExporterStdout {
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout trace exporter: %w", err)
+		}
+		return exporter, nil
+	}
+
 	// Create Google Cloud Trace exporter
 	exporter, err := gcptrace.New(
 		gcptrace.WithProjectID(p.config.ProjectID),
 		gcptrace.WithTimeout(p.config.ExportTimeout),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create Google Cloud Trace exporter: %w", err)
+		return nil, fmt.Errorf("failed to create Google Cloud Trace exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// sampler returns the configured Sampler, or one for TraceRatio wrapped to
+// respect the parent's decision when ParentBased is set
+func (p *Provider) sampler() sdktrace.Sampler {
+	if p.config.Sampler != nil {
+		return p.config.Sampler
 	}
 
-	// Configure sampler
 	var sampler sdktrace.Sampler
 	if p.config.TraceRatio >= 1.0 {
 		sampler = sdktrace.AlwaysSample()
@@ -157,26 +266,18 @@ func (p *Provider) setupTracing(ctx context.Context) error {
 	} else {
 		sampler = sdktrace.TraceIDRatioBased(p.config.TraceRatio)
 	}
-
-	// Create tracer provider with batch processing
-	p.tracerProvider = sdktrace.NewTracerProvider(
-		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sampler),
-		sdktrace.WithBatcher(exporter,
-			sdktrace.WithBatchTimeout(p.config.BatchTimeout),
-			sdktrace.WithMaxExportBatchSize(p.config.MaxBatchSize),
-			sdktrace.WithMaxQueueSize(p.config.MaxQueueSize),
-		),
-	)
-
-	// Set as global tracer provider
-	otel.SetTracerProvider(p.tracerProvider)
-
-	return nil
+	if p.config.ParentBased {
+		sampler = sdktrace.ParentBased(sampler)
+	}
+	return sampler
 }
 
 // createResource creates resource with GCP detection
 func (p *Provider) createResource(ctx context.Context) (*resource.Resource, error) {
+	if p.config.Resource != nil {
+		return p.config.Resource, nil
+	}
+
 	// Base service attributes
 	baseAttrs := []attribute.KeyValue{
 		semconv.ServiceName(p.config.ServiceName),
@@ -208,11 +309,23 @@ func (p *Provider) createResource(ctx context.Context) (*resource.Resource, erro
 
 // setupPropagation configures trace context propagation
 func (p *Provider) setupPropagation() {
-	otel.SetTextMapPropagator(
-		propagation.NewCompositeTextMapPropagator(
-			propagation.TraceContext{},
-			propagation.Baggage{},
-		),
+	otel.SetTextMapPropagator(p.Propagator())
+}
+
+// Propagator returns the configured propagator, or NewPropagator by default
+func (p *Provider) Propagator() propagation.TextMapPropagator {
+	if p.config.Propagator != nil {
+		return p.config.Propagator
+	}
+	return NewPropagator()
+}
+
+// NewPropagator returns the W3C trace context and baggage propagator that
+// NewProvider installs globally
+func NewPropagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
 	)
 }
 
@@ -255,8 +368,9 @@ func (p *Provider) GetServiceName() string {
 	return p.config.ServiceName
 }
 
-// Global provider instance
-var globalProvider *Provider
+// Global provider instance, swapped atomically so request goroutines can read
+// it while tests reset it
+var globalProvider atomic.Pointer[Provider]
 
 // InitGlobal initializes the global telemetry provider
 func InitGlobal(ctx context.Context, config Config) error {
@@ -264,35 +378,52 @@ func InitGlobal(ctx context.Context, config Config) error {
 	if err != nil {
 		return err
 	}
-	globalProvider = provider
+	globalProvider.Store(provider)
 	return nil
 }
 
+// SetGlobal makes p the global telemetry provider. NewProvider has already
+// installed its tracer provider and propagator unless it is isolated.
+func SetGlobal(p *Provider) {
+	globalProvider.Store(p)
+}
+
 // Global returns the global telemetry provider
 func Global() *Provider {
-	return globalProvider
+	return globalProvider.Load()
+}
+
+// ResetGlobal forgets the global provider and resets the OpenTelemetry
+// tracer provider and propagator to no-ops. It does not shut the previous
+// provider down; call ShutdownGlobal first. It is safe to call while other
+// goroutines use the global, but parallel tests still see each other's
+// globals; give them isolated providers instead.
+func ResetGlobal() {
+	globalProvider.Store(nil)
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
 }
 
 // ShutdownGlobal shuts down the global telemetry provider
 func ShutdownGlobal(ctx context.Context) error {
-	if globalProvider != nil {
-		return globalProvider.Shutdown(ctx)
+	if p := globalProvider.Load(); p != nil {
+		return p.Shutdown(ctx)
 	}
 	return nil
 }
 
 // GetGlobalTracer returns a tracer from the global provider
 func GetGlobalTracer(name string, opts ...trace.TracerOption) trace.Tracer {
-	if globalProvider != nil {
-		return globalProvider.GetTracer(name, opts...)
+	if p := globalProvider.Load(); p != nil {
+		return p.GetTracer(name, opts...)
 	}
 	return otel.Tracer(name, opts...)
 }
 
 // GetGlobalProjectID returns the project ID from the global provider
 func GetGlobalProjectID() string {
-	if globalProvider != nil {
-		return globalProvider.GetProjectID()
+	if p := globalProvider.Load(); p != nil {
+		return p.GetProjectID()
 	}
 	return ""
 }
@@ -3,8 +3,17 @@ package telemetry
 import (
 	"context"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestConfig_SetDefaults(t *testing.T) {
@@ -154,6 +163,40 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "stdout exporter does not need project ID",
+			config: Config{
+				ServiceName:   "test",
+				EnableTracing: true,
+				Exporter:      ExporterStdout,
+				TraceRatio:    0.5,
+				MaxBatchSize:  512,
+				MaxQueueSize:  2048,
+			},
+			wantErr: false,
+		},
+		{
+			name: "custom span exporter does not need project ID",
+			config: Config{
+				ServiceName:   "test",
+				EnableTracing: true,
+				SpanExporter:  tracetest.NewInMemoryExporter(),
+				MaxBatchSize:  512,
+				MaxQueueSize:  2048,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown exporter",
+			config: Config{
+				ServiceName:  "test",
+				ProjectID:    "test-project",
+				Exporter:     "jaeger",
+				MaxBatchSize: 512,
+				MaxQueueSize: 2048,
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid trace ratio - negative",
 			config: Config{
@@ -247,6 +290,41 @@ func TestNewProvider(t *testing.T) {
 	})
 }
 
+func TestProviderStdoutExporter(t *testing.T) {
+	provider, err := NewProvider(context.Background(), Config{
+		ServiceName:   "test-service",
+		EnableTracing: true,
+		Exporter:      ExporterStdout,
+		TraceRatio:    1,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(context.Background())
+
+	if provider.tracerProvider == nil {
+		t.Error("Expected tracer provider for stdout exporter")
+	}
+}
+
+func TestProviderSampler(t *testing.T) {
+	tests := []struct {
+		ratio       float64
+		parentBased bool
+		want        string
+	}{
+		{1, false, "AlwaysOnSampler"},
+		{0.25, false, "TraceIDRatioBased{0.25}"},
+		{0.25, true, "ParentBased{root:TraceIDRatioBased{0.25},"},
+	}
+	for _, tt := range tests {
+		p := &Provider{config: Config{TraceRatio: tt.ratio, ParentBased: tt.parentBased}}
+		if got := p.sampler().Description(); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("sampler(%v, %v) = %q, want prefix %q", tt.ratio, tt.parentBased, got, tt.want)
+		}
+	}
+}
+
 func TestProviderGetTracer(t *testing.T) {
 	ctx := context.Background()
 
@@ -327,6 +405,134 @@ func TestProviderShutdown(t *testing.T) {
 	}
 }
 
+func TestProviderOverrides(t *testing.T) {
+	ctx := context.Background()
+	exporter := tracetest.NewInMemoryExporter()
+	res := resource.NewSchemaless(attribute.String("service.name", "override"))
+
+	provider, err := NewProvider(ctx, Config{
+		ServiceName:   "test-service",
+		EnableTracing: true,
+		TraceRatio:    0.5,
+		SpanExporter:  exporter,
+		Sampler:       sdktrace.AlwaysSample(),
+		Resource:      res,
+		Propagator:    propagation.Baggage{},
+		Isolated:      true,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	if got := provider.sampler().Description(); got != "AlwaysOnSampler" {
+		t.Errorf("sampler = %q, want the override", got)
+	}
+	if _, ok := provider.Propagator().(propagation.Baggage); !ok {
+		t.Errorf("Propagator() = %T, want the override", provider.Propagator())
+	}
+
+	_, span := provider.Tracer().Start(ctx, "op")
+	span.End()
+	if err := provider.tracerProvider.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush() error = %v", err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || !spans[0].Resource.Equal(res) {
+		t.Errorf("spans = %v, want one span with the override resource", spans)
+	}
+}
+
+func TestIsolatedProvider(t *testing.T) {
+	ctx := context.Background()
+	ResetGlobal()
+	t.Cleanup(ResetGlobal)
+	before := otel.GetTracerProvider()
+
+	provider, err := NewProvider(ctx, Config{
+		ServiceName:   "test-service",
+		EnableTracing: true,
+		Exporter:      ExporterStdout,
+		Isolated:      true,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	if otel.GetTracerProvider() != before {
+		t.Error("isolated provider replaced the global tracer provider")
+	}
+	if provider.TracerProvider() == before {
+		t.Error("isolated provider returned the global tracer provider")
+	}
+}
+
+func TestSetGlobal(t *testing.T) {
+	t.Cleanup(ResetGlobal)
+
+	provider, err := NewProvider(context.Background(), Config{ServiceName: "test-service", ProjectID: "test-project"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	SetGlobal(provider)
+	if Global() != provider {
+		t.Error("SetGlobal() did not set the global provider")
+	}
+}
+
+func TestResetGlobal(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	if err := InitGlobal(ctx, Config{ServiceName: "test-service", ProjectID: "test-project"}); err != nil {
+		t.Fatalf("InitGlobal() error = %v", err)
+	}
+	ResetGlobal()
+
+	if Global() != nil {
+		t.Error("ResetGlobal() kept the global provider")
+	}
+	if fields := otel.GetTextMapPropagator().Fields(); len(fields) != 0 {
+		t.Errorf("propagator fields = %v, want none", fields)
+	}
+}
+
+func TestResetGlobalConcurrent(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	// Run with -race: readers must not race with ResetGlobal and SetGlobal
+	provider, err := NewProvider(ctx, Config{ServiceName: "test-service", ProjectID: "test-project", Isolated: true})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_, span := StartSpanFromContext(ctx, "concurrent")
+					span.End()
+					GetGlobalProjectID()
+				}
+			}
+		}()
+	}
+	for range 20 {
+		SetGlobal(provider)
+		ResetGlobal()
+	}
+	close(stop)
+	wg.Wait()
+}
+
 func TestGlobalProvider(t *testing.T) {
 	ctx := context.Background()
 
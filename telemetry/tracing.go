@@ -6,6 +6,9 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/sirhco/go-gcp-middleware/errs"
+	"github.com/sirhco/go-gcp-middleware/internal/attrconv"
+	"github.com/sirhco/go-gcp-middleware/internal/stack"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -58,8 +61,8 @@ func StartSpan(ctx context.Context, tracerName, spanName string, opts ...SpanOpt
 // StartSpanFromContext starts a span using the tracer from the global provider
 func StartSpanFromContext(ctx context.Context, spanName string, opts ...SpanOptions) (context.Context, trace.Span) {
 	tracerName := "default"
-	if globalProvider != nil {
-		tracerName = globalProvider.config.ServiceName
+	if p := globalProvider.Load(); p != nil {
+		tracerName = p.config.ServiceName
 	}
 	return StartSpan(ctx, tracerName, spanName, opts...)
 }
@@ -118,11 +121,28 @@ func RecordError(span trace.Span, err error, description string, attrs ...attrib
 		attribute.String("error.type", fmt.Sprintf("%T", err)),
 		attribute.String("error.message", err.Error()),
 	}
+	allAttrs = append(allAttrs, errorChainAttributes(err)...)
 	allAttrs = append(allAttrs, attrs...)
 
 	span.RecordError(err, trace.WithAttributes(allAttrs...))
 }
 
+// errorChainAttributes returns the wrapped error types, the fields attached
+// with errs.With and the innermost captured stack of err
+func errorChainAttributes(err error) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if types := errs.Types(err); len(types) > 0 {
+		attrs = append(attrs, attribute.StringSlice("error.chain", types))
+	}
+	for _, a := range errs.Fields(err) {
+		attrs = append(attrs, attrconv.FromSlog(a.Key, a.Value.Resolve()))
+	}
+	if pcs := errs.Stack(err); pcs != nil {
+		attrs = append(attrs, attribute.String("exception.stacktrace", stack.Format(err.Error(), pcs)))
+	}
+	return attrs
+}
+
 // RecordErrorContext records an error on the current span from context
 func RecordErrorContext(ctx context.Context, err error, description string, attrs ...attribute.KeyValue) {
 	span := trace.SpanFromContext(ctx)
@@ -3,11 +3,16 @@ package telemetry
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/sirhco/go-gcp-middleware/errs"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -112,6 +117,38 @@ func TestRecordError(t *testing.T) {
 	// Note: We can't directly verify this without more complex mocking
 }
 
+func TestRecordErrorChain(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	_, span := tracer.Start(context.Background(), "op")
+
+	err := fmt.Errorf("charging card: %w", errs.With(errs.New("declined"), "order_id", 42))
+	RecordError(span, err, "payment failed")
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range events[0].Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["order_id"]; got.AsInt64() != 42 {
+		t.Errorf("order_id = %v, want 42", got.Emit())
+	}
+	if got := attrs["error.chain"].AsStringSlice(); len(got) != 1 || got[0] != "*fmt.wrapError" {
+		t.Errorf("error.chain = %v", got)
+	}
+	got := attrs["exception.stacktrace"].AsString()
+	if !strings.HasPrefix(got, err.Error()+"\n\ngoroutine 1 [running]:\n") {
+		t.Errorf("exception.stacktrace has unexpected header: %q", got)
+	}
+	if !strings.Contains(got, "TestRecordErrorChain") {
+		t.Errorf("exception.stacktrace = %q, want creation frame", got)
+	}
+}
+
 func TestRecordErrorContext(t *testing.T) {
 	ctx := context.Background()
 	ctx, span := StartSpanFromContext(ctx, "test-span")